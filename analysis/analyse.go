@@ -5,6 +5,7 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -20,28 +21,57 @@ var logf = fmt.Printf
 
 // var logf = func(_ string, _ ...interface{}) {}
 
-var cliArguments = struct{ requireErrorCodes bool }{}
-
-func init() {
-	Analyzer.Flags.BoolVar(&cliArguments.requireErrorCodes, "strict", false, "if this flag is set, exported error returning functions are required to declare error codes")
+// Settings configures an instance of the analyzer.
+type Settings struct {
+	Strict         bool   `json:"strict"`         // require exported error returning functions to declare error codes
+	Infer          bool   `json:"infer"`          // infer the error codes of undocumented functions
+	Config         string `json:"config"`         // path to the configuration file, searched up to the module root if empty
+	Baseline       string `json:"baseline"`       // path to a baseline file, or empty
+	UpdateBaseline bool   `json:"baselineUpdate"` // rewrite the baseline file instead of reporting diagnostics
 }
 
-var Analyzer = &analysis.Analyzer{
-	Name:     "serum",
-	Doc:      "Checks that any function that has a structured docstring enumerating Serum-style error codes is telling the truth.",
-	Requires: []*analysis.Analyzer{inspect.Analyzer},
-	Run:      runVerify,
-	FactTypes: []analysis.Fact{
-		new(ErrorCodes),
-		new(ErrorConstructor),
-		new(ErrorType),
-		new(ErrorInterface),
-	},
+// Analyzer is the analyzer used by the go-serum-analyzer command, configured by its command line flags.
+var Analyzer = NewAnalyzer(Settings{})
+
+// NewAnalyzer creates a new instance of the analyzer, that uses the given settings.
+// The settings can be changed using the command line flags of the returned analyzer.
+//
+// Each instance has its own settings, so different instances can be used in the same process,
+// e.g. by linter plugins.
+func NewAnalyzer(settings Settings) *analysis.Analyzer {
+	analyzer := &analysis.Analyzer{
+		Name:       "serum",
+		Doc:        "Checks that any function that has a structured docstring enumerating Serum-style error codes is telling the truth.",
+		Requires:   []*analysis.Analyzer{inspect.Analyzer, newConfigAnalyzer(&settings)},
+		Run:        runVerify,
+		ResultType: reflect.TypeOf((*Result)(nil)),
+		FactTypes: []analysis.Fact{
+			new(ErrorCodes),
+			new(ErrorConstructor),
+			new(ErrorType),
+			new(ErrorInterface),
+		},
+	}
+
+	analyzer.Flags.BoolVar(&settings.Strict, "strict", settings.Strict, "if this flag is set, exported error returning functions are required to declare error codes")
+	analyzer.Flags.BoolVar(&settings.Infer, "infer", settings.Infer, "if this flag is set, the error codes of undocumented functions are inferred and can be used by callers in other packages")
+	analyzer.Flags.StringVar(&settings.Config, "config", settings.Config, "path to the configuration file (by default \".serum.json\" or \".serum.yaml\" is searched up to the module root)")
+	analyzer.Flags.StringVar(&settings.Baseline, "baseline", settings.Baseline, "path to a baseline file: diagnostics recorded in the baseline are not reported")
+	analyzer.Flags.BoolVar(&settings.UpdateBaseline, "baseline-update", settings.UpdateBaseline, "if this flag is set, the baseline file is rewritten with the current diagnostics instead of reporting them")
+	return analyzer
 }
 
 type (
 	ErrorCodes struct {
 		Codes CodeSet
+
+		// Inferred is set if the function does not declare error codes,
+		// and the codes were inferred from its implementation instead (see the "-infer" flag).
+		Inferred bool
+
+		// Attributes contains the attributes of the codes, only for codes having any.
+		// Attributes propagate to callers: e.g. a code declared as internal by a called function is internal for its callers, too.
+		Attributes map[string]CodeAttributes
 	}
 
 	// ErrorConstructor is a fact that is used to tag functions that are error constructors,
@@ -59,6 +89,12 @@ func (*ErrorCodes) AFact() {}
 func (e *ErrorCodes) String() string {
 	codes := e.Codes.Slice()
 	sort.Strings(codes)
+	for i, code := range codes {
+		codes[i] = e.Attributes[code].prefix() + code
+	}
+	if e.Inferred {
+		return fmt.Sprintf("ErrorCodes (inferred): %v", strings.Join(codes, " "))
+	}
 	return fmt.Sprintf("ErrorCodes: %v", strings.Join(codes, " "))
 }
 
@@ -70,17 +106,19 @@ func (e *ErrorConstructor) String() string {
 
 type (
 	context struct {
-		pass     *analysis.Pass
-		lookup   *funcLookup
-		scc      scc.State
-		comments ast.CommentMap
+		pass       *analysis.Pass
+		lookup     *funcLookup
+		scc        scc.State
+		comments   ast.CommentMap
+		attributes map[string]CodeAttributes // known attributes of error codes (see findCodeAttributes)
 	}
 
 	funcCodesMap map[*ast.FuncDecl]funcCodes
 
 	funcCodes struct {
-		codes CodeSet
-		param *funcCodeParam
+		codes      CodeSet
+		param      *funcCodeParam
+		attributes map[string]CodeAttributes // attributes declared in the docs, only for codes having any
 	}
 
 	funcCodeParam struct {
@@ -133,7 +171,7 @@ func runVerify(pass *analysis.Pass) (interface{}, error) {
 
 	// Out of funcsToAnalyse get all functions that declare error codes and the actual codes they declare.
 	// In the remaining analysis we only look at the functions that declare error codes or get called by an analysed function.
-	funcClaims := findClaimedErrorCodes(pass, funcsToAnalyse)
+	funcClaims, undocumentedFuncs := findClaimedErrorCodes(pass, funcsToAnalyse)
 	exportErrorConstructorFacts(pass, funcClaims)
 
 	// Okay -- let's look at the functions that have made claims about their error codes.
@@ -142,24 +180,51 @@ func runVerify(pass *analysis.Pass) (interface{}, error) {
 	// When we reach other function calls that declare their errors, that's good enough info (assuming they're also being checked for truthfulness).
 	// Anything else is trouble.
 	scc := scc.StartSCC() // SCC for handling of recursive functions
-	c := &context{pass, lookup, scc, comments}
+	c := &context{pass, lookup, scc, comments, findCodeAttributes(pass, funcClaims)}
+	known := findKnownCodes(pass)
 	for funcDecl, claims := range funcClaims {
 		foundCodes, ok := lookup.foundCodes[funcDecl]
 		if !ok {
 			foundCodes = findErrorCodesInFunc(c, &funcDefinition{funcDecl, nil})
 		}
 
-		reportIfCodesDoNotMatch(pass, funcDecl, foundCodes, claims.codes)
+		reportIfCodesDoNotMatch(pass, lookup, funcDecl, foundCodes, claims.codes, known)
 	}
 
+	inferErrorCodes(c, undocumentedFuncs)
+
 	// Export all claimed error codes as facts.
 	// Missing error code docs or unused ones will get reported in the respective functions,
 	// but on caller site only the documented behaviour matters.
-	exportErrorCodeFacts(pass, funcClaims)
+	exportErrorCodeFacts(c, funcClaims)
+
+	// The called functions of the current package have facts now, so the contributors to declared codes can be found.
+	for funcDecl, claims := range funcClaims {
+		checkFunctionCodeBudget(c, funcDecl, claims.codes)
+		checkFunctionBoundaries(c, funcDecl, claims.codes)
+		checkFunctionInternalCodes(c, funcDecl, claims.codes)
+		checkFunctionDeprecatedCodes(c, funcDecl, claims)
+	}
+	checkInterfaceInternalCodes(c, interfaces)
+	findDeprecatedCodeComparisons(c)
+	findDiscardedErrors(c)
+	findUnreachableHandlers(c)
+	findTypedNilReturns(c)
+	findStaleErrorReturns(c)
+	expectations := findCodeExpectations(c)
 
 	findConversionsToErrorReturningInterfaces(c)
 
-	return nil, nil
+	if baseline := getPackageConfig(pass).baseline; baseline != nil && baseline.update {
+		if err := writeBaseline(pass, baseline); err != nil {
+			return nil, err
+		}
+	}
+
+	result := newResult(pass, lookup)
+	result.docs = findDocCoverage(pass, funcsToAnalyse)
+	result.expectations = expectations
+	return result, nil
 }
 
 var tError = types.NewInterfaceType([]*types.Func{
@@ -215,6 +280,20 @@ func isMethod(funcDecl *ast.FuncDecl) bool {
 	return funcDecl != nil && funcDecl.Recv != nil && len(funcDecl.Recv.List) == 1
 }
 
+// isExportedFunc checks if the given function is part of the public API of the package:
+// its name and, for methods, the name of its receiver type are exported.
+func isExportedFunc(pass *analysis.Pass, funcDecl *ast.FuncDecl) bool {
+	if !funcDecl.Name.IsExported() {
+		return false
+	}
+	if !isMethod(funcDecl) {
+		return true
+	}
+
+	named := getNamedType(pass.TypesInfo.TypeOf(funcDecl.Recv.List[0].Type))
+	return named != nil && named.Obj().Exported()
+}
+
 // getNamedType casts the given type to *types.Named if possible,
 // unpacking pointers if they occur.
 // getNamedType returns nil, if said conversion fails.
@@ -257,11 +336,11 @@ func createCommentMap(pass *analysis.Pass) ast.CommentMap {
 }
 
 // findErrorDocs looks at the given comments and tries to find error code declarations.
-func findErrorDocs(comments *ast.CommentGroup) (CodeSet, string, bool, error) {
+func findErrorDocs(comments *ast.CommentGroup) (*ErrorDocs, error) {
 	if comments == nil {
-		return nil, "", false, nil
+		return &ErrorDocs{}, nil
 	}
-	return findErrorDocsSM{}.run(comments.Text())
+	return ParseErrorDocs(comments.Text())
 }
 
 // findErrorReturningFunctions looks for functions that return an error,
@@ -289,19 +368,16 @@ func findErrorReturningFunctions(pass *analysis.Pass, lookup *funcLookup) []*ast
 // checkFunctionReturnsError determines if the given type is a function that returns an error.
 // If the last result is not an error but one of the other results is, it emits a diagnostic.
 func checkFunctionReturnsError(pass *analysis.Pass, funcType *ast.FuncType) bool {
-	resultsList := funcType.Results
-	if resultsList == nil {
+	if funcType.Results == nil {
 		return false
 	}
 
-	lastResult := resultsList.List[len(resultsList.List)-1]
-	typ := pass.TypesInfo.TypeOf(lastResult.Type)
-	if !types.Implements(typ, tError) {
+	if !returnsError(pass, funcType) {
 		// Emit diagnostic if an error is returned as non-last argument
-		for _, result := range resultsList.List {
+		for _, result := range funcType.Results.List {
 			typ := pass.TypesInfo.TypeOf(result.Type)
 			if types.Implements(typ, tError) {
-				pass.ReportRangef(result, "error should be returned as the last argument")
+				reportRange(pass, categoryErrorPosition, result, "error should be returned as the last argument")
 			}
 		}
 		return false
@@ -310,24 +386,40 @@ func checkFunctionReturnsError(pass *analysis.Pass, funcType *ast.FuncType) bool
 	return true
 }
 
+// returnsError determines if the given type is a function that returns an error as its last result.
+func returnsError(pass *analysis.Pass, funcType *ast.FuncType) bool {
+	resultsList := funcType.Results
+	if resultsList == nil {
+		return false
+	}
+
+	lastResult := resultsList.List[len(resultsList.List)-1]
+	return types.Implements(pass.TypesInfo.TypeOf(lastResult.Type), tError)
+}
+
 // findClaimedErrorCodes finds the error codes claimed by the given functions,
-// and emits diagnostics if a function does not claim error codes or
-// if the format of the docstring does not match the expected format.
-func findClaimedErrorCodes(pass *analysis.Pass, funcsToAnalyse []*ast.FuncDecl) funcCodesMap {
+// and emits diagnostics if the format of the docstring does not match the expected format.
+//
+// The second result contains the functions that do not claim any error codes.
+// Cause() methods of error types are not included, because they don't have to declare error codes.
+func findClaimedErrorCodes(pass *analysis.Pass, funcsToAnalyse []*ast.FuncDecl) (funcCodesMap, []*ast.FuncDecl) {
 	result := funcCodesMap{}
+	var undocumented []*ast.FuncDecl
 	for _, funcDecl := range funcsToAnalyse {
-		codes, errorCodeParamName, declaredNoCodesOk, err := findErrorDocs(funcDecl.Doc)
+		docs, err := findErrorDocs(funcDecl.Doc)
 		if err != nil {
-			pass.Reportf(funcDecl.Pos(), "function %q has odd docstring: %s", funcDecl.Name.Name, err)
+			report(pass, categoryDocFormat, funcDecl.Pos(), "function %q has odd docstring: %s", funcDecl.Name.Name, err)
 			continue
 		}
+		codes := docs.Codes
+		checkDeclaredCodesRegistered(pass, funcDecl.Doc, codes)
 
-		errorCodeParam, ok := findErrorCodeParamIdent(pass, funcDecl.Type, errorCodeParamName)
+		errorCodeParam, ok := findErrorCodeParamIdent(pass, funcDecl.Type, docs.Param)
 		if !ok {
 			continue
 		}
 
-		if len(codes) == 0 && !declaredNoCodesOk && errorCodeParam == nil {
+		if len(codes) == 0 && !docs.NoCodes && errorCodeParam == nil {
 			// Exclude Cause() methods of error types from having to declare error codes.
 			// If a Cause() method declares error codes, treat it like every other method.
 			if isMethod(funcDecl) {
@@ -337,17 +429,77 @@ func findClaimedErrorCodes(pass *analysis.Pass, funcsToAnalyse []*ast.FuncDecl)
 				}
 			}
 
-			// Warn directly about any functions that are exported if they return errors,
-			// but don't declare error codes in their docs.
-			if cliArguments.requireErrorCodes && funcDecl.Name.IsExported() {
-				pass.Reportf(funcDecl.Pos(), "function %q is exported, but does not declare any error codes", funcDecl.Name.Name)
-			}
+			undocumented = append(undocumented, funcDecl)
 		} else {
-			result[funcDecl] = funcCodes{codes, errorCodeParam}
+			result[funcDecl] = funcCodes{codes, errorCodeParam, docs.Attributes}
 		}
 	}
 
-	return result
+	return result, undocumented
+}
+
+// inferErrorCodes handles the given functions, which return errors but don't declare error codes.
+//
+// In strict mode, exported functions are reported.
+// If inference is enabled, the error codes of the functions are found like those of functions declaring error codes,
+// and are exported as inferred ErrorCodes facts, so they can be used by callers in other packages.
+// Reported functions then get a suggested fix declaring the inferred codes.
+func inferErrorCodes(c *context, undocumented []*ast.FuncDecl) {
+	pass, lookup := c.pass, c.lookup
+	config := getPackageConfig(pass)
+
+	for _, funcDecl := range undocumented {
+		// Warn directly about any functions that are exported if they return errors,
+		// but don't declare error codes in their docs.
+		reportMissingDoc := config.strict && funcDecl.Name.IsExported()
+		if !config.infer {
+			if reportMissingDoc {
+				report(pass, categoryMissingDoc, funcDecl.Pos(), "function %q is exported, but does not declare any error codes", funcDecl.Name.Name)
+			}
+			continue
+		}
+
+		codes, ok := lookup.foundCodes[funcDecl]
+		if !ok {
+			codes = findErrorCodesInFunc(c, &funcDefinition{funcDecl, nil})
+		}
+		exportErrorCodesFact(pass, funcDecl.Name, &ErrorCodes{Codes: codes, Inferred: true, Attributes: c.attributesOf(codes)})
+
+		if reportMissingDoc {
+			emit(pass, analysis.Diagnostic{
+				Pos:            funcDecl.Pos(),
+				Category:       categoryMissingDoc,
+				Message:        fmt.Sprintf("function %q is exported, but does not declare any error codes", funcDecl.Name.Name),
+				SuggestedFixes: inferredDocFix(funcDecl, codes),
+			}, nil)
+		}
+	}
+}
+
+// inferredDocFix suggests to declare the given codes in the docstring of the given function.
+func inferredDocFix(funcDecl *ast.FuncDecl, codes CodeSet) []analysis.SuggestedFix {
+	var declaration strings.Builder
+	if len(codes) == 0 {
+		declaration.WriteString("// Errors: none")
+	} else {
+		sorted := codes.Slice()
+		sort.Strings(sorted)
+		declaration.WriteString("// Errors:\n//")
+		for _, code := range sorted {
+			fmt.Fprintf(&declaration, "\n//    - %s --", code)
+		}
+	}
+
+	// Append the declaration to an existing docstring, or add it as new docstring.
+	edit := analysis.TextEdit{Pos: funcDecl.Pos(), End: funcDecl.Pos(), NewText: []byte(declaration.String() + "\n")}
+	if funcDecl.Doc != nil {
+		edit = analysis.TextEdit{Pos: funcDecl.Doc.End(), End: funcDecl.Doc.End(), NewText: []byte("\n//\n" + declaration.String())}
+	}
+
+	return []analysis.SuggestedFix{{
+		Message:   "Declare the inferred error codes",
+		TextEdits: []analysis.TextEdit{edit},
+	}}
 }
 
 // findErrorCodeParamIdent tries to find the error code param identifier in the parameter list
@@ -367,7 +519,7 @@ func findErrorCodeParamIdent(pass *analysis.Pass, funcType *ast.FuncType, errorC
 
 			basic, ok := pass.TypesInfo.TypeOf(paramIdent).(*types.Basic)
 			if !ok || basic.Name() != "string" {
-				pass.ReportRangef(paramIdent, "error code parameter %q has to be of type string", errorCodeParamName)
+				reportRange(pass, categoryErrorConstructor, paramIdent, "error code parameter %q has to be of type string", errorCodeParamName)
 				return nil, false
 			}
 
@@ -375,7 +527,7 @@ func findErrorCodeParamIdent(pass *analysis.Pass, funcType *ast.FuncType, errorC
 		}
 	}
 
-	pass.Reportf(funcType.Pos(), "declared error code parameter %q could not be found in parameter list", errorCodeParamName)
+	report(pass, categoryErrorConstructor, funcType.Pos(), "declared error code parameter %q could not be found in parameter list", errorCodeParamName)
 	return nil, false
 }
 
@@ -407,14 +559,14 @@ func exportErrorConstructorFact(pass *analysis.Pass, funcIdent *ast.Ident, param
 }
 
 // exportErrorCodeFacts exports all codes for each function in the given map as facts.
-func exportErrorCodeFacts(pass *analysis.Pass, codes funcCodesMap) {
+func exportErrorCodeFacts(c *context, codes funcCodesMap) {
 	for funcDecl, funcCodes := range codes {
-		exportErrorCodesFact(pass, funcDecl.Name, funcCodes.codes)
+		exportErrorCodesFact(c.pass, funcDecl.Name, &ErrorCodes{Codes: funcCodes.codes, Attributes: c.attributesOf(funcCodes.codes)})
 	}
 }
 
-// exportErrorCodesFact exports all given codes for the given function as an ErrorCodes fact.
-func exportErrorCodesFact(pass *analysis.Pass, funcIdent *ast.Ident, codes CodeSet) {
+// exportErrorCodesFact exports the given ErrorCodes fact for the given function.
+func exportErrorCodesFact(pass *analysis.Pass, funcIdent *ast.Ident, fact *ErrorCodes) {
 	definition, ok := pass.TypesInfo.Defs[funcIdent]
 	if !ok {
 		logf("Could not find definition for function %q!", funcIdent.Name)
@@ -427,7 +579,6 @@ func exportErrorCodesFact(pass *analysis.Pass, funcIdent *ast.Ident, codes CodeS
 		return
 	}
 
-	fact := &ErrorCodes{codes}
 	pass.ExportObjectFact(fn, fact)
 }
 
@@ -454,13 +605,55 @@ func checkIfErrorCodesMatch(foundCodes CodeSet, claimedCodes CodeSet) (bool, str
 }
 
 // reportIfCodesDoNotMatch emits a diagnostic if the given code collections don't match.
-func reportIfCodesDoNotMatch(pass *analysis.Pass, funcDecl *ast.FuncDecl, foundCodes CodeSet, claimedCodes CodeSet) {
+// The return statements returning undeclared codes are attached to the diagnostic as related information,
+// and likely misspellings are suggested with the help of the known codes (see findTypos).
+func reportIfCodesDoNotMatch(pass *analysis.Pass, lookup *funcLookup, funcDecl *ast.FuncDecl, foundCodes CodeSet, claimedCodes CodeSet, known CodeSet) {
 	errorCodesMatch, errorMessage := checkIfErrorCodesMatch(foundCodes, claimedCodes)
 	if !errorCodesMatch {
-		pass.Reportf(funcDecl.Pos(), "function %q has a mismatch of declared and actual error codes: %s", funcDecl.Name.Name, errorMessage)
+		missingCodes, unusedCodes := Difference(foundCodes, claimedCodes), Difference(claimedCodes, foundCodes)
+		codes := Union(missingCodes, unusedCodes).Slice()
+		typos := findTypos(missingCodes, unusedCodes, known)
+		emit(pass, analysis.Diagnostic{
+			Pos:            funcDecl.Type.Pos(),
+			End:            funcDecl.Type.End(),
+			Category:       categoryCodeMismatch,
+			Message:        fmt.Sprintf("function %q has a mismatch of declared and actual error codes: %s%s", funcDecl.Name.Name, errorMessage, typoMessage(typos)),
+			SuggestedFixes: typoFix(funcDecl.Doc, typos),
+			Related:        findReturnSites(lookup, funcDecl, missingCodes),
+		}, codes)
 	}
 }
 
+// findReturnSites finds the return statements of the given function, that may return any of the given codes.
+func findReturnSites(lookup *funcLookup, funcDecl *ast.FuncDecl, codes CodeSet) []analysis.RelatedInformation {
+	var result []analysis.RelatedInformation
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		switch stmt := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			var returned []string
+			for code := range lookup.returnCodes[stmt] {
+				if _, ok := codes[code]; ok {
+					returned = append(returned, code)
+				}
+			}
+
+			if len(returned) > 0 {
+				sort.Strings(returned)
+				result = append(result, analysis.RelatedInformation{
+					Pos:     stmt.Pos(),
+					End:     stmt.End(),
+					Message: fmt.Sprintf("undeclared error codes returned here: %s", strings.Join(returned, " ")),
+				})
+			}
+			return false
+		}
+		return true
+	})
+	return result
+}
+
 // findErrorCodesInFunc finds error codes that are returned by the given function.
 // The result is also stored in the foundCodes cache of the given funcLookup.
 func findErrorCodesInFunc(c *context, function *funcDefinition) CodeSet {
@@ -492,6 +685,7 @@ func findErrorCodesInFunc(c *context, function *funcDefinition) CodeSet {
 // findErrorCodesInFunctionReturnStmts looks at all return statement of the given (error returning) function
 // and figures out which error codes may be returned by that statement.
 func findErrorCodesInFunctionReturnStmts(c *context, visitedIdents map[*ast.Object]struct{}, function *funcDefinition) CodeSet {
+	lookup := c.lookup
 	result := Set()
 
 	ast.Inspect(function.body(), func(node ast.Node) bool {
@@ -501,6 +695,7 @@ func findErrorCodesInFunctionReturnStmts(c *context, visitedIdents map[*ast.Obje
 		case *ast.ReturnStmt:
 			annotations := getReturnStmtAnnotations(c, stmt)
 			if annotations != nil && annotations.shouldOverwrite {
+				lookup.returnCodes[stmt] = annotations.overwrite
 				result = Union(result, annotations.overwrite)
 				return false
 			}
@@ -511,6 +706,7 @@ func findErrorCodesInFunctionReturnStmts(c *context, visitedIdents map[*ast.Obje
 				returnCodes = Union(returnCodes, annotations.addCodes)
 			}
 
+			lookup.returnCodes[stmt] = returnCodes
 			result = Union(result, returnCodes)
 			return false
 		}
@@ -597,7 +793,7 @@ func findErrorCodesInExpression(c *context, visitedIdents map[*ast.Object]struct
 		}
 
 		// If it's not fulfilling the error interface it's not supported
-		pass.ReportRangef(expr, "expression %T does not implement valid error type", expr)
+		reportRange(pass, categoryUnsupported, expr, "expression %T does not implement valid error type", expr)
 		return nil
 	case *ast.CompositeLit, *ast.BasicLit: // Actual value creation!
 		return extractErrorCodesFromAffector(pass, lookup, startingFunc, expr)
@@ -605,16 +801,16 @@ func findErrorCodesInExpression(c *context, visitedIdents map[*ast.Object]struct
 		return findErrorCodesFromIdentTaint(c, visitedIdents, expr.Sel, startingFunc)
 	case *ast.TypeAssertExpr:
 		if expr.Type == nil {
-			pass.ReportRangef(expr, "type assertion switch is not supported in error code analysis")
+			reportRange(pass, categoryUnsupported, expr, "type assertion switch is not supported in error code analysis")
 			return nil
 		}
-		pass.ReportRangef(expr, "type assertion is not supported in error code analysis")
+		reportRange(pass, categoryUnsupported, expr, "type assertion is not supported in error code analysis")
 		return nil
 	case *ast.IndexExpr:
-		pass.ReportRangef(expr, "expression is not supported in error code analysis")
+		reportRange(pass, categoryUnsupported, expr, "expression is not supported in error code analysis")
 		return nil
 	default:
-		pass.ReportRangef(expr, "expression %T is not supported in error code analysis", expr)
+		reportRange(pass, categoryUnsupported, expr, "expression %T is not supported in error code analysis", expr)
 		return nil
 	}
 }
@@ -643,12 +839,14 @@ func findErrorCodesFromFunctionCall(c *context, startingFunc *funcDefinition, ca
 	code, ok := extractErrorCodeFromConstructorCall(pass, startingFunc, calledFunction, callee, callExpr)
 	if ok {
 		result.Add(code)
+		lookup.addCodeSource(startingFunc, codeSource{callee, nil, Set(code)})
 	}
 
 	// We first look if the error codes are already computed and stored as a fact.
 	// If so we use those, otherwise we try to recurse and compute error codes for that function.
 	var fact ErrorCodes
 	if callee != nil && pass.ImportObjectFact(callee, &fact) {
+		lookup.addCodeSource(startingFunc, codeSource{callee, nil, fact.Codes})
 		return Union(result, fact.Codes)
 	}
 
@@ -656,6 +854,9 @@ func findErrorCodesFromFunctionCall(c *context, startingFunc *funcDefinition, ca
 	if callExpr != nil && callee == nil {
 		conversionCodes := extractErrorCodesFromTypeConversion(pass, callExpr)
 		if len(conversionCodes) > 0 {
+			if named := getNamedType(pass.TypesInfo.TypeOf(callExpr.Fun)); named != nil {
+				lookup.addCodeSource(startingFunc, codeSource{named.Obj(), nil, conversionCodes})
+			}
 			return Union(result, conversionCodes)
 		}
 	}
@@ -669,8 +870,10 @@ func findErrorCodesFromFunctionCall(c *context, startingFunc *funcDefinition, ca
 
 			if ok {
 				calledFuncDef.funcDecl = function
+			} else if isCalleeInAllowedPackage(pass, callee) {
+				return Set()
 			} else {
-				pass.ReportRangef(calledExpression, "function %q in dot-imported package does not declare error codes", calledExpression.Name)
+				reportRange(pass, categoryUndeclaredCallee, calledExpression, "function %q in dot-imported package does not declare error codes", calledExpression.Name)
 				return Set()
 			}
 		} else {
@@ -691,7 +894,10 @@ func findErrorCodesFromFunctionCall(c *context, startingFunc *funcDefinition, ca
 		if target, ok := astutil.Unparen(calledExpression.X).(*ast.Ident); ok {
 			if obj, ok := pass.TypesInfo.ObjectOf(target).(*types.PkgName); ok {
 				// We're calling a function in a package that does not have declared error codes
-				pass.ReportRangef(calledExpression, "function %q in package %q does not declare error codes", calledExpression.Sel.Name, obj.Imported().Name())
+				if isCalleeInAllowedPackage(pass, callee) {
+					return Set()
+				}
+				reportRange(pass, categoryUndeclaredCallee, calledExpression, "function %q in package %q does not declare error codes", calledExpression.Sel.Name, obj.Imported().Name())
 				return Set()
 			}
 		}
@@ -703,11 +909,15 @@ func findErrorCodesFromFunctionCall(c *context, startingFunc *funcDefinition, ca
 	case *ast.FuncLit:
 		calledFuncDef.funcLit = calledExpression
 	default:
-		pass.ReportRangef(calledExpression, "invalid error source: definition of the unnamed function could not be found")
+		reportRange(pass, categoryUnsupported, calledExpression, "invalid error source: definition of the unnamed function could not be found")
 		return Set()
 	}
 
 	if calledFuncDef.funcDecl != nil || calledFuncDef.funcLit != nil {
+		// The codes of the called function are only complete after the analysis of its component,
+		// so they are looked up when the sources are resolved.
+		lookup.addCodeSource(startingFunc, codeSource{callee, calledFuncDef.node(), nil})
+
 		shouldRecurse := scc.HandleEdge(startingFunc.node(), calledFuncDef.node())
 		if shouldRecurse {
 			newCodes := findErrorCodesInFunc(c, &calledFuncDef)
@@ -716,9 +926,11 @@ func findErrorCodesFromFunctionCall(c *context, startingFunc *funcDefinition, ca
 		} else if cachedResult, ok := lookup.foundCodes[calledFuncDef.node()]; ok {
 			result = Union(result, cachedResult)
 		}
+	} else if isCalleeInAllowedPackage(pass, callee) {
+		// Methods defined in allowed packages don't need to declare error codes.
 	} else {
 		// Could e.g. be a method which is defined in another package
-		pass.ReportRangef(calledFunction, "called function does not declare error codes")
+		reportRange(pass, categoryUndeclaredCallee, calledFunction, "called function does not declare error codes")
 	}
 
 	return result
@@ -733,14 +945,14 @@ func findErrorCodesFromAllAssignedLambdas(c *context, ident *ast.Ident, function
 
 	for _, badIdent := range taintResult.identOutOfScope {
 		if function.funcDecl != nil { // expression is inside a function
-			pass.ReportRangef(badIdent, "error returning function literal may not be a parameter, receiver or global variable")
+			reportRange(pass, categoryErrorSource, badIdent, "error returning function literal may not be a parameter, receiver or global variable")
 		} else { // expression is inside a lambda (function literal)
-			pass.ReportRangef(badIdent, "error returning function literal may not be a parameter, global variable or other variables declared outside of the function body")
+			reportRange(pass, categoryErrorSource, badIdent, "error returning function literal may not be a parameter, global variable or other variables declared outside of the function body")
 		}
 	}
 
 	for _, destruct := range taintResult.destructAssignment {
-		pass.ReportRangef(destruct.source, "unsupported: assigning result of function call to variable %q is not allowed", destruct.target.Name)
+		reportRange(pass, categoryUnsupported, destruct.source, "unsupported: assigning result of function call to variable %q is not allowed", destruct.target.Name)
 	}
 
 	result := Set()
@@ -758,6 +970,7 @@ func findErrorCodesInLambdaAssignment(c *context, ident *ast.Ident, assignedExpr
 	switch rhsEntry := astutil.Unparen(assignedExpr).(type) {
 	case *ast.FuncLit:
 		result = findErrorCodesInFunc(c, &funcDefinition{nil, rhsEntry})
+		c.lookup.addCodeSource(function, codeSource{nil, rhsEntry, nil})
 	case *ast.Ident: // name of a function
 		callee := pass.TypesInfo.Uses[rhsEntry]
 		result = findErrorCodesFromFunctionCall(c, function, rhsEntry, callee, nil)
@@ -770,7 +983,7 @@ func findErrorCodesInLambdaAssignment(c *context, ident *ast.Ident, assignedExpr
 		}
 		result = findErrorCodesFromFunctionCall(c, function, rhsEntry, callee, nil)
 	default:
-		pass.ReportRangef(rhsEntry, "unsupported: assignment to variable %q can only be an identifier or function literal", ident.Name)
+		reportRange(pass, categoryUnsupported, rhsEntry, "unsupported: assignment to variable %q can only be an identifier or function literal", ident.Name)
 	}
 
 	return result
@@ -784,9 +997,9 @@ func findErrorCodesFromIdentTaint(c *context, visitedIdents map[*ast.Object]stru
 
 	for _, badIdent := range taintResult.identOutOfScope {
 		if function.funcDecl != nil { // expression is inside a function
-			pass.ReportRangef(badIdent, "returned error may not be a parameter, receiver or global variable")
+			reportRange(pass, categoryErrorSource, badIdent, "returned error may not be a parameter, receiver or global variable")
 		} else { // expression is inside a lambda (function literal)
-			pass.ReportRangef(badIdent, "returned error may not be a parameter, global variable or other variables declared outside of the function body")
+			reportRange(pass, categoryErrorSource, badIdent, "returned error may not be a parameter, global variable or other variables declared outside of the function body")
 		}
 	}
 
@@ -808,7 +1021,7 @@ func findErrorCodesFromIdentTaint(c *context, visitedIdents map[*ast.Object]stru
 			// Destructuring mode.
 			// We're going to make some crass simplifications here, and say... if this is anything other than the last arg, you're not supported.
 			if destruct.position != funType.Results().Len()-1 {
-				pass.ReportRangef(destruct.target, "unsupported: tracking error codes for function call with error as non-last return argument")
+				reportRange(pass, categoryUnsupported, destruct.target, "unsupported: tracking error codes for function call with error as non-last return argument")
 				continue
 			}
 
@@ -913,7 +1126,7 @@ func findCodesAssignedToErrorCodeFieldInAssignment(pass *analysis.Pass, function
 		}
 
 		if len(assignment.Lhs) != len(assignment.Rhs) {
-			pass.ReportRangef(assignment.Rhs[0], "error code has to be constant value or error code parameter")
+			reportRange(pass, categoryInvalidCode, assignment.Rhs[0], "error code has to be constant value or error code parameter")
 			continue
 		}
 
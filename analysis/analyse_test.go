@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
 )
 
@@ -13,6 +14,18 @@ func TestVerifyAnalyzer(t *testing.T) {
 	for _, pattern := range []string{
 		"001",
 		"annotation",
+		"budget/relaxed", "budget",
+		"boundary/db", "boundary/api", "boundary/api/internal",
+		"internalcodes/internal/retry", "internalcodes",
+		"deprecated/legacy", "deprecated",
+		"discarded",
+		"handlers",
+		"infer/inner", "infer",
+		"typednil",
+		"stale",
+		"expectations",
+		"config", "config/lenient", "config/naming", "config/excluded", "config/generated", "config/prefix",
+		"configyaml", "registry",
 		"docformat",
 		"dotimport/inner1", "dotimport",
 		"error_constructor",
@@ -34,6 +47,10 @@ func TestVerifyAnalyzer(t *testing.T) {
 	}
 }
 
+func TestInferSuggestedFixes(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), Analyzer, "infer/inner", "infer")
+}
+
 type collector struct {
 	data map[string]struct{}
 }
@@ -122,3 +139,25 @@ func TestIsErrorCodeValid(t *testing.T) {
 		}
 	}
 }
+
+func TestNewAnalyzer(t *testing.T) {
+	first := NewAnalyzer(Settings{Strict: true, Config: "first.yaml"})
+	second := NewAnalyzer(Settings{})
+	if err := second.Flags.Set("config", "second.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		analyzer       *analysis.Analyzer
+		flag, expected string
+	}{
+		{first, "strict", "true"},
+		{first, "config", "first.yaml"},
+		{second, "strict", "false"},
+		{second, "config", "second.yaml"},
+	} {
+		if actual := test.analyzer.Flags.Lookup(test.flag).Value.String(); actual != test.expected {
+			t.Errorf("flag %q should be %q but was %q", test.flag, test.expected, actual)
+		}
+	}
+}
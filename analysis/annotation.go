@@ -3,6 +3,7 @@ package analysis
 import (
 	"fmt"
 	"go/ast"
+	"sort"
 	"strings"
 )
 
@@ -58,12 +59,12 @@ func getReturnStmtAnnotations(c *context, stmt *ast.ReturnStmt) *annotationRetur
 		var err error
 		line = strings.TrimSpace(line)
 		if len(line) == 0 {
-			pass.ReportRangef(stmt, "error in annotation: expected '=', '+=', '-=', '+code', or '-code' after '%s' indicator", annotationIndicatorReturnStmt)
+			reportRange(pass, categoryAnnotation, stmt, "error in annotation: expected '=', '+=', '-=', '+code', or '-code' after '%s' indicator", annotationIndicatorReturnStmt)
 			return nil
 		}
 
 		if result != nil {
-			pass.ReportRangef(stmt, "found multiple annotations for the same return statement: only one is allowed")
+			reportRange(pass, categoryAnnotation, stmt, "found multiple annotations for the same return statement: only one is allowed")
 		}
 		result = &annotationReturnStmt{false, Set(), Set(), Set()}
 
@@ -88,11 +89,19 @@ func getReturnStmtAnnotations(c *context, stmt *ast.ReturnStmt) *annotationRetur
 		}
 
 		if err != nil {
-			pass.ReportRangef(stmt, "%v", err)
+			reportRange(pass, categoryAnnotation, stmt, "%v", err)
 			return nil
 		}
 	}
 
+	if result != nil {
+		codes := Union(Union(result.overwrite, result.addCodes), result.subCodes).Slice()
+		sort.Strings(codes)
+		for _, code := range codes {
+			checkErrorCodeRegistered(pass, stmt, code)
+		}
+	}
+
 	return result
 }
 
@@ -0,0 +1,45 @@
+package analysis
+
+import "golang.org/x/tools/go/analysis"
+
+// findCodeAttributes collects the known attributes of error codes:
+// the attributes declared in the docs of the given functions,
+// the attributes of all ErrorCodes facts of the current package and its dependencies,
+// and the deprecations of the registry.
+//
+// Attributes are properties of the codes themselves, so they are merged over all declarations of a code.
+func findCodeAttributes(pass *analysis.Pass, funcClaims funcCodesMap) map[string]CodeAttributes {
+	result := map[string]CodeAttributes{}
+	add := func(attributes map[string]CodeAttributes) {
+		for code, attribute := range attributes {
+			result[code] = result[code].merge(attribute)
+		}
+	}
+
+	for _, fact := range pass.AllObjectFacts() {
+		if codes, ok := fact.Fact.(*ErrorCodes); ok {
+			add(codes.Attributes)
+		}
+	}
+	for _, claims := range funcClaims {
+		add(claims.attributes)
+	}
+	add(getPackageConfig(pass).registeredAttrs)
+
+	return result
+}
+
+// attributesOf returns the known attributes of the given codes, only for codes having any.
+// If none of the codes has attributes, nil is returned.
+func (c *context) attributesOf(codes CodeSet) map[string]CodeAttributes {
+	var result map[string]CodeAttributes
+	for code := range codes {
+		if attributes, ok := c.attributes[code]; ok {
+			if result == nil {
+				result = map[string]CodeAttributes{}
+			}
+			result[code] = attributes
+		}
+	}
+	return result
+}
@@ -0,0 +1,281 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Baseline is a record of known diagnostics, which are not reported again.
+//
+// Diagnostics are identified by the declaration they occur in, their category and the error codes they are about,
+// but not by their position: this way the baseline survives edits to the code.
+type Baseline struct {
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// BaselineEntry describes Count diagnostics, that share the same key.
+type BaselineEntry struct {
+	Package  string   `json:"package"`
+	Function string   `json:"function"`
+	Category string   `json:"category"`
+	Codes    []string `json:"codes,omitempty"`
+	Count    int      `json:"count"`
+}
+
+type baselineKey struct {
+	function string
+	category string
+	codes    string
+}
+
+// packageBaseline holds the baseline state of a single package.
+type packageBaseline struct {
+	path      string // path of the baseline file
+	update    bool
+	remaining map[baselineKey]int // number of diagnostics per key that are still suppressed
+	recorded  map[baselineKey]int // diagnostics per key that were found, only used in update mode
+}
+
+// baselineFileLock serializes updates to the baseline file within one process.
+var baselineFileLock sync.Mutex
+
+// loadPackageBaseline reads the baseline file and returns the state for the package with the given path.
+// If the file does not exist an empty baseline is used.
+func loadPackageBaseline(path, pkgPath string, update bool) (*packageBaseline, error) {
+	result := &packageBaseline{path, update, map[baselineKey]int{}, map[baselineKey]int{}}
+	if update {
+		return result, nil
+	}
+
+	baseline, err := readBaseline(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range baseline.Entries {
+		if entry.Package == pkgPath {
+			key := baselineKey{entry.Function, entry.Category, strings.Join(entry.Codes, " ")}
+			result.remaining[key] += entry.Count
+		}
+	}
+	return result, nil
+}
+
+// isBaselinePackage checks if the package of the given pass is part of the analysed project,
+// which is the module containing the working directory.
+//
+// The analyzer also runs on dependencies to compute facts, but their diagnostics are never shown:
+// packages of the standard library, of other modules and of vendor directories are not part of the baseline.
+// If the working directory is not inside a module, only the standard library is excluded.
+func isBaselinePackage(pass *analysis.Pass) bool {
+	if len(pass.Files) == 0 {
+		return false
+	}
+	dir := filepath.Dir(pass.Fset.File(pass.Files[0].Pos()).Name())
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	root := findModuleRoot(wd)
+	if root == "" {
+		goroot := filepath.Join(build.Default.GOROOT, "src")
+		return !strings.HasPrefix(dir, goroot+string(filepath.Separator))
+	}
+	if findModuleRoot(dir) != root {
+		return false
+	}
+
+	relative, err := filepath.Rel(root, dir)
+	if err != nil {
+		return false
+	}
+	for _, element := range strings.Split(filepath.ToSlash(relative), "/") {
+		if element == "vendor" {
+			return false
+		}
+	}
+	return true
+}
+
+// findModuleRoot searches the given directory and its parents for a "go.mod" file,
+// and returns the directory containing it. If no module was found, the empty string is returned.
+func findModuleRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// readBaseline reads the baseline file at the given path.
+// If the file does not exist, an empty baseline is returned.
+func readBaseline(path string) (*Baseline, error) {
+	baseline := &Baseline{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return baseline, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read baseline: %v", err)
+	}
+
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("invalid baseline %q: %v", path, err)
+	}
+	return baseline, nil
+}
+
+// suppresses checks if the given diagnostic is part of the baseline.
+//
+// In update mode every diagnostic is recorded instead, and suppressed.
+func (baseline *packageBaseline) suppresses(pass *analysis.Pass, diagnostic analysis.Diagnostic, codes []string) bool {
+	sortedCodes := append([]string(nil), codes...)
+	sort.Strings(sortedCodes)
+	key := baselineKey{enclosingDeclarationName(pass, diagnostic.Pos), diagnostic.Category, strings.Join(sortedCodes, " ")}
+
+	if baseline.update {
+		baseline.recorded[key]++
+		return true
+	}
+
+	if baseline.remaining[key] > 0 {
+		baseline.remaining[key]--
+		return true
+	}
+	return false
+}
+
+// writeBaseline replaces all entries of the package of the given pass in the baseline file
+// with the diagnostics recorded during the analysis.
+func writeBaseline(pass *analysis.Pass, baseline *packageBaseline) error {
+	path := baseline.path
+	baselineFileLock.Lock()
+	defer baselineFileLock.Unlock()
+
+	pkgPath := pass.Pkg.Path()
+	old, err := readBaseline(path)
+	if err != nil {
+		return err
+	}
+
+	result := Baseline{Entries: []BaselineEntry{}}
+	for _, entry := range old.Entries {
+		if entry.Package != pkgPath {
+			result.Entries = append(result.Entries, entry)
+		}
+	}
+
+	for key, count := range baseline.recorded {
+		var codes []string
+		if key.codes != "" {
+			codes = strings.Split(key.codes, " ")
+		}
+		result.Entries = append(result.Entries, BaselineEntry{pkgPath, key.function, key.category, codes, count})
+	}
+
+	sort.Slice(result.Entries, func(i, j int) bool {
+		a, b := result.Entries[i], result.Entries[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		if a.Function != b.Function {
+			return a.Function < b.Function
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return strings.Join(a.Codes, " ") < strings.Join(b.Codes, " ")
+	})
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(result); err != nil {
+		return fmt.Errorf("could not encode baseline: %v", err)
+	}
+
+	if err := ioutil.WriteFile(path, buffer.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("could not write baseline: %v", err)
+	}
+	return nil
+}
+
+// enclosingDeclarationName finds the top level declaration containing the given position and returns its name.
+//
+// Functions are named "Func", methods are named "Type.Method" or "(*Type).Method".
+// For other declarations the name of the first declared identifier is used.
+// If no declaration contains the position, the empty string is returned.
+func enclosingDeclarationName(pass *analysis.Pass, pos token.Pos) string {
+	for _, file := range pass.Files {
+		if pos < file.Pos() || pos > file.End() {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			if pos < decl.Pos() || pos > decl.End() {
+				continue
+			}
+
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				return funcDeclName(pass, decl)
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if pos < spec.Pos() || pos > spec.End() {
+						continue
+					}
+
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						return spec.Name.Name
+					case *ast.ValueSpec:
+						return spec.Names[0].Name
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// funcDeclName returns the name of the given function as "Func", "Type.Method" or "(*Type).Method".
+func funcDeclName(pass *analysis.Pass, funcDecl *ast.FuncDecl) string {
+	if !isMethod(funcDecl) {
+		return funcDecl.Name.Name
+	}
+
+	receiver := pass.TypesInfo.TypeOf(funcDecl.Recv.List[0].Type)
+	named := getNamedType(receiver)
+	if named == nil {
+		return funcDecl.Name.Name
+	}
+
+	if _, ok := receiver.(*types.Pointer); ok {
+		return fmt.Sprintf("(*%s).%s", named.Obj().Name(), funcDecl.Name.Name)
+	}
+	return fmt.Sprintf("%s.%s", named.Obj().Name(), funcDecl.Name.Name)
+}
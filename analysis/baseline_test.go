@@ -0,0 +1,43 @@
+package analysis
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestBaseline(t *testing.T) {
+	analyzer := NewAnalyzer(Settings{
+		Strict:   true,
+		Baseline: filepath.Join(analysistest.TestData(), "src", "baseline", "serum-baseline.json"),
+	})
+	analysistest.Run(t, analysistest.TestData(), analyzer, "baseline")
+}
+
+func TestBaselineUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "serum-baseline.json")
+	analyzer := NewAnalyzer(Settings{Strict: true, Baseline: path, UpdateBaseline: true})
+
+	// All diagnostics are written to the baseline instead of being reported,
+	// so the expectations in the test data are not met: ignore them.
+	// Only the entries of the analysed package are written, not the ones of its dependencies.
+	c := &collector{data: map[string]struct{}{}}
+	analysistest.Run(c, analysistest.TestData(), analyzer, "baseline")
+
+	baseline, err := readBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []BaselineEntry{
+		{"baseline", "Changed", categoryCodeMismatch, []string{"baseline-error-known", "baseline-error-new"}, 1},
+		{"baseline", "Known", categoryCodeMismatch, []string{"baseline-error-known", "baseline-error-other"}, 1},
+		{"baseline", "Twice", categoryUnsupported, nil, 2},
+		{"baseline", "Undeclared", categoryMissingDoc, nil, 1},
+	}
+	if !reflect.DeepEqual(expected, baseline.Entries) {
+		t.Errorf("expected baseline entries %v but got %v", expected, baseline.Entries)
+	}
+}
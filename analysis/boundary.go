@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"path"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkFunctionBoundaries emits a diagnostic for each error code declared by the given exported function,
+// that is forbidden by a boundary of the package. The diagnostic lists the called functions introducing the code.
+func checkFunctionBoundaries(c *context, funcDecl *ast.FuncDecl, codes CodeSet) {
+	if !isExportedFunc(c.pass, funcDecl) {
+		return
+	}
+
+	forEachForbiddenCode(c.pass, codes, func(code string, boundary BoundaryConfig, pattern string) {
+		reportBoundary(c.pass, funcDecl.Name, fmt.Sprintf("function %q", funcDecl.Name.Name), code, boundary, pattern,
+			"; introduced by: "+describeCodeSources(c, funcDecl, code))
+	})
+}
+
+// checkInterfaceMethodBoundaries emits a diagnostic for each error code declared by the given exported interface method,
+// that is forbidden by a boundary of the package.
+func checkInterfaceMethodBoundaries(pass *analysis.Pass, methodIdent *ast.Ident, codes CodeSet) {
+	if !methodIdent.IsExported() {
+		return
+	}
+
+	forEachForbiddenCode(pass, codes, func(code string, boundary BoundaryConfig, pattern string) {
+		reportBoundary(pass, methodIdent, fmt.Sprintf("interface method %q", methodIdent.Name), code, boundary, pattern, "")
+	})
+}
+
+// forEachForbiddenCode calls the given function for each of the given codes, that matches a code pattern
+// of a boundary of the current package, in sorted order. Only the first matching pattern is passed for each code.
+func forEachForbiddenCode(pass *analysis.Pass, codes CodeSet, fn func(code string, boundary BoundaryConfig, pattern string)) {
+	boundaries := getPackageConfig(pass).boundaries
+	if len(boundaries) == 0 {
+		return
+	}
+
+	sorted := codes.Slice()
+	sort.Strings(sorted)
+	for _, code := range sorted {
+		if boundary, pattern, ok := findForbiddingBoundary(boundaries, code); ok {
+			fn(code, boundary, pattern)
+		}
+	}
+}
+
+// findForbiddingBoundary returns the first of the given boundaries with a code pattern matching the given code.
+func findForbiddingBoundary(boundaries []BoundaryConfig, code string) (BoundaryConfig, string, bool) {
+	for _, boundary := range boundaries {
+		for _, pattern := range boundary.Codes {
+			if ok, _ := path.Match(pattern, code); ok { // patterns are already validated
+				return boundary, pattern, true
+			}
+		}
+	}
+	return BoundaryConfig{}, "", false
+}
+
+func reportBoundary(pass *analysis.Pass, rng analysis.Range, subject, code string, boundary BoundaryConfig, pattern, details string) {
+	var reason string
+	if boundary.Reason != "" {
+		reason = ": " + boundary.Reason
+	}
+
+	reportCodes(pass, categoryCodeLeak, rng, []string{code},
+		"%s declares error code %q, which must not cross the boundary of package %q (forbidden by %q)%s%s",
+		subject, code, pass.Pkg.Path(), pattern, reason, details)
+}
@@ -0,0 +1,81 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// maxContributors is the number of called functions listed in diagnostics about exceeded code budgets.
+const maxContributors = 3
+
+// codeContributor is a called function, together with the number of declared codes it contributes.
+type codeContributor struct {
+	name  string
+	codes int
+}
+
+// checkFunctionCodeBudget emits a diagnostic if the given exported function declares more error codes,
+// than the configuration of the package allows. The diagnostic lists the called functions contributing the most codes.
+func checkFunctionCodeBudget(c *context, funcDecl *ast.FuncDecl, codes CodeSet) {
+	maxCodes := getPackageConfig(c.pass).maxCodes
+	if maxCodes == 0 || len(codes) <= maxCodes || !isExportedFunc(c.pass, funcDecl) {
+		return
+	}
+
+	contributors := findCodeContributors(c, funcDecl, codes)
+	reportCodeBudget(c.pass, funcDecl.Name, fmt.Sprintf("function %q", funcDecl.Name.Name), codes, maxCodes, contributors)
+}
+
+// checkInterfaceMethodCodeBudget emits a diagnostic if the given exported interface method declares more error codes,
+// than the configuration of the package allows.
+func checkInterfaceMethodCodeBudget(pass *analysis.Pass, methodIdent *ast.Ident, codes CodeSet) {
+	maxCodes := getPackageConfig(pass).maxCodes
+	if maxCodes == 0 || len(codes) <= maxCodes || !methodIdent.IsExported() {
+		return
+	}
+
+	reportCodeBudget(pass, methodIdent, fmt.Sprintf("interface method %q", methodIdent.Name), codes, maxCodes, nil)
+}
+
+func reportCodeBudget(pass *analysis.Pass, rng analysis.Range, subject string, codes CodeSet, maxCodes int, contributors []codeContributor) {
+	sorted := codes.Slice()
+	sort.Strings(sorted)
+
+	var details string
+	if len(contributors) > 0 {
+		parts := make([]string, len(contributors))
+		for i, contributor := range contributors {
+			parts[i] = fmt.Sprintf("%s (%d)", contributor.name, contributor.codes)
+		}
+		details = "; most codes come from: " + strings.Join(parts, ", ")
+	}
+
+	reportCodes(pass, categoryCodeBudget, rng, sorted, "%s declares %d error codes, more than the limit of %d: %s%s",
+		subject, len(codes), maxCodes, strings.Join(sorted, " "), details)
+}
+
+// findCodeContributors finds the functions called by the given function, that return any of the given codes.
+// It returns up to maxContributors functions, sorted by the number of contributed codes.
+func findCodeContributors(c *context, funcDecl *ast.FuncDecl, codes CodeSet) []codeContributor {
+	contributed := findCalleeCodes(c, funcDecl, codes)
+
+	result := make([]codeContributor, 0, len(contributed))
+	for name, codes := range contributed {
+		result = append(result, codeContributor{name, len(codes)})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].codes != result[j].codes {
+			return result[i].codes > result[j].codes
+		}
+		return result[i].name < result[j].name
+	})
+
+	if len(result) > maxContributors {
+		result = result[:maxContributors]
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// findCalleeCodes finds the functions called by the given function, that return any of the given codes.
+// It maps the names of the called functions (see calleeName) to the codes they return.
+func findCalleeCodes(c *context, funcDecl *ast.FuncDecl, codes CodeSet) map[string]CodeSet {
+	pass := c.pass
+	result := map[string]CodeSet{}
+
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		callExpr, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		callee, ok := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+		if !ok {
+			return true
+		}
+
+		name := calleeName(pass, callee)
+		for code := range calleeCodes(c, callee) {
+			if _, ok := codes[code]; ok {
+				if result[name] == nil {
+					result[name] = Set()
+				}
+				result[name].Add(code)
+			}
+		}
+		return true
+	})
+
+	return result
+}
+
+// describeCodeSources returns a description of where the given function gets the given code from:
+// the sorted names of the called functions returning it, or "this function" if the code is not returned by any called function.
+func describeCodeSources(c *context, funcDecl *ast.FuncDecl, code string) string {
+	callees := findCalleeCodes(c, funcDecl, Set(code))
+	if len(callees) == 0 {
+		return "this function"
+	}
+
+	names := make([]string, 0, len(callees))
+	for name := range callees {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// calleeCodes returns the error codes of the given called function:
+// the codes of its ErrorCodes fact, or the codes found by the analysis for undocumented functions of the current package.
+func calleeCodes(c *context, callee *types.Func) CodeSet {
+	var fact ErrorCodes
+	if c.pass.ImportObjectFact(callee, &fact) {
+		return fact.Codes
+	}
+	if callee.Pkg() != c.pass.Pkg {
+		return nil
+	}
+
+	var result CodeSet
+	c.lookup.forEach(func(funcDecl *ast.FuncDecl) {
+		if funcDecl.Name.Pos() == callee.Pos() {
+			result = c.lookup.foundCodes[funcDecl]
+		}
+	})
+	return result
+}
+
+// calleeName returns the name of the given function as "Func" or "Type.Method",
+// qualified with the package name for functions of other packages.
+func calleeName(pass *analysis.Pass, fn *types.Func) string {
+	name := fn.Name()
+	if receiver := fn.Type().(*types.Signature).Recv(); receiver != nil {
+		if named := getNamedType(receiver.Type()); named != nil {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+
+	if fn.Pkg() != nil && fn.Pkg() != pass.Pkg {
+		name = fn.Pkg().Name() + "." + name
+	}
+	return name
+}
@@ -0,0 +1,615 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the configuration files, in order of precedence.
+var configFileNames = []string{".serum.json", ".serum.yaml", ".serum.yml"}
+
+const (
+	generatedReport = "report" // report diagnostics in generated files (default)
+	generatedIgnore = "ignore" // suppress all diagnostics in generated files
+)
+
+// Config is the project configuration of the analyzer.
+//
+// The configuration is read from a ".serum.json" or ".serum.yaml" file.
+// The file is discovered by searching the directory of the analysed package and its parents,
+// up to the module root (the directory containing the "go.mod" file).
+// Alternatively the file can be given explicitly with the "-config" flag.
+type Config struct {
+	// Strict overrides the "-strict" flag for all packages, if set.
+	Strict *bool `json:"strict,omitempty" yaml:"strict,omitempty"`
+
+	// Infer overrides the "-infer" flag for all packages, if set.
+	Infer *bool `json:"infer,omitempty" yaml:"infer,omitempty"`
+
+	// Packages contains settings for packages matching a pattern.
+	// If multiple entries match a package, later entries take precedence.
+	Packages []PackageConfig `json:"packages,omitempty" yaml:"packages,omitempty"`
+
+	// Exclude lists patterns of packages for which no diagnostics are reported.
+	// Excluded packages are still analysed, so facts about their error codes are available to other packages.
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+
+	// Generated is either "report" (default) or "ignore".
+	// If set to "ignore", no diagnostics are reported for generated files.
+	Generated string `json:"generated,omitempty" yaml:"generated,omitempty"`
+
+	// AllowedExternalPackages lists patterns of packages that may be called
+	// even though they don't declare error codes.
+	AllowedExternalPackages []string `json:"allowedExternalPackages,omitempty" yaml:"allowedExternalPackages,omitempty"`
+
+	// DisabledCategories lists categories of diagnostics that are not reported.
+	DisabledCategories []string `json:"disabledCategories,omitempty" yaml:"disabledCategories,omitempty"`
+
+	// CodePrefix is a template for the prefix all error codes created in a package have to start with.
+	// (See PackageConfig.CodePrefix.)
+	CodePrefix string `json:"codePrefix,omitempty" yaml:"codePrefix,omitempty"`
+
+	// MaxCodes is the maximum number of error codes an exported function or interface method may declare.
+	// Zero means that there is no limit.
+	MaxCodes int `json:"maxCodes,omitempty" yaml:"maxCodes,omitempty"`
+
+	// Boundaries lists rules forbidding error codes of lower layers in the API of higher layers.
+	// (See BoundaryConfig.)
+	Boundaries []BoundaryConfig `json:"boundaries,omitempty" yaml:"boundaries,omitempty"`
+
+	// Registry is the path of the error code registry, relative to the configuration file.
+	// If set, all error codes have to be registered. (See Registry.)
+	Registry string `json:"registry,omitempty" yaml:"registry,omitempty"`
+}
+
+// PackageConfig contains the settings for all packages matching Pattern.
+type PackageConfig struct {
+	// Pattern is an import path pattern, where "..." matches any string (e.g. "example.org/storage/...").
+	Pattern string `json:"pattern" yaml:"pattern"`
+
+	// Strict overrides the "-strict" flag for the matched packages, if set.
+	Strict *bool `json:"strict,omitempty" yaml:"strict,omitempty"`
+
+	// Infer overrides the "-infer" flag for the matched packages, if set.
+	Infer *bool `json:"infer,omitempty" yaml:"infer,omitempty"`
+
+	// CodePattern is a regular expression, all error codes created in the matched packages have to match.
+	CodePattern string `json:"codePattern,omitempty" yaml:"codePattern,omitempty"`
+
+	// CodePrefix is a template for the prefix all error codes created in the matched packages have to start with.
+	// It overrides the top level CodePrefix. The following placeholders are replaced for each package:
+	//   - "{module}": the name of the module, which is the last element of the module path up to the first dot (e.g. "acme" for "acme.io").
+	//   - "{package}": the import path relative to the module root, with slashes replaced by dashes (e.g. "storage-blob"),
+	//     or the package name for the root package of the module.
+	//   - "{name}": the package name.
+	// If the module of a package cannot be determined, the first element of the import path is used as module.
+	CodePrefix string `json:"codePrefix,omitempty" yaml:"codePrefix,omitempty"`
+
+	// MaxCodes overrides the top level MaxCodes for the matched packages, if set. Zero means that there is no limit.
+	MaxCodes *int `json:"maxCodes,omitempty" yaml:"maxCodes,omitempty"`
+
+	// DisabledCategories lists additional categories of diagnostics that are not reported for the matched packages.
+	DisabledCategories []string `json:"disabledCategories,omitempty" yaml:"disabledCategories,omitempty"`
+}
+
+// BoundaryConfig forbids error codes in the exported API of packages matching one of Packages.
+//
+// Exported functions and interface methods of the matched packages must not declare any error code matching one of Codes.
+// This keeps the codes of lower layers (e.g. the database layer) from leaking through API boundaries:
+// they have to be translated into codes of the boundary package instead.
+type BoundaryConfig struct {
+	// Packages are import path patterns of the packages forming the boundary (e.g. "acme.io/api/...").
+	Packages []string `json:"packages" yaml:"packages"`
+
+	// Codes are glob patterns of the forbidden error codes, as accepted by path.Match (e.g. "db-*").
+	Codes []string `json:"codes" yaml:"codes"`
+
+	// Reason is an optional explanation, which is added to the reported diagnostics.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// packageConfig is the configuration resolved for a single package.
+type packageConfig struct {
+	strict             bool
+	infer              bool // infer error codes of undocumented functions
+	excluded           bool
+	generatedFiles     map[*token.File]struct{} // files for which diagnostics are suppressed, or nil
+	allowedExternal    []string
+	codePattern        *regexp.Regexp
+	codePrefix         string
+	maxCodes           int              // maximum number of codes of exported functions, or 0
+	boundaries         []BoundaryConfig // boundaries matching the package
+	disabledCategories map[string]struct{}
+	baseline           *packageBaseline          // baseline of known diagnostics, or nil for dependencies
+	registeredCodes    CodeSet                   // codes of the registry, or nil if no registry is configured
+	registeredAttrs    map[string]CodeAttributes // attributes of deprecated codes of the registry
+}
+
+// newConfigAnalyzer creates an analyzer loading the configuration for each package, using the given settings.
+// Its result is used by the serum analyzer and is retrieved with getPackageConfig.
+func newConfigAnalyzer(settings *Settings) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name: "serumconfig",
+		Doc:  "Loads the project configuration of the serum analyzer for the analysed package.",
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			return runConfig(pass, settings)
+		},
+		ResultType: reflect.TypeOf((*packageConfig)(nil)),
+	}
+}
+
+func runConfig(pass *analysis.Pass, settings *Settings) (interface{}, error) {
+	configFile := settings.Config
+	if configFile == "" && len(pass.Files) > 0 {
+		dir := filepath.Dir(pass.Fset.File(pass.Files[0].Pos()).Name())
+		configFile = findConfigFile(dir)
+	}
+
+	config := &Config{}
+	if configFile != "" {
+		var err error
+		config, err = loadConfig(configFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := config.resolve(pass, settings)
+	if config.Registry != "" {
+		registry, err := ReadRegistry(config.registryPath(configFile))
+		if err != nil {
+			return nil, err
+		}
+		result.registeredCodes = registry.codeSet()
+		result.registeredAttrs = registry.attributes()
+	}
+
+	if settings.Baseline != "" && isBaselinePackage(pass) {
+		var err error
+		result.baseline, err = loadPackageBaseline(settings.Baseline, pass.Pkg.Path(), settings.UpdateBaseline)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// getPackageConfig returns the configuration for the package of the given pass.
+//
+// Each instance of the serum analyzer requires its own config analyzer,
+// so the configuration is the only result of type *packageConfig.
+func getPackageConfig(pass *analysis.Pass) *packageConfig {
+	for _, result := range pass.ResultOf {
+		if config, ok := result.(*packageConfig); ok {
+			return config
+		}
+	}
+	panic("should be unreachable: the serum analyzer requires a config analyzer")
+}
+
+// findConfigFile searches the given directory and its parents for a configuration file.
+// The search stops at the module root. If no file was found, the empty string is returned.
+func findConfigFile(dir string) string {
+	for {
+		for _, name := range configFileNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return ""
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// configCache holds the parsed configuration files by absolute path,
+// so that a file is not read again for every analysed package.
+var configCache sync.Map // map[string]cachedConfig
+
+type cachedConfig struct {
+	modTime time.Time
+	size    int64
+	config  *Config
+}
+
+// loadConfig returns the configuration file at the given path like readConfig,
+// but reuses the parsed configuration as long as the file is unchanged.
+func loadConfig(path string) (*Config, error) {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read configuration: %v", err)
+	}
+	info, err := os.Stat(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not read configuration: %v", err)
+	}
+	if value, ok := configCache.Load(key); ok {
+		cached := value.(cachedConfig)
+		if cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+			return cached.config, nil
+		}
+	}
+
+	config, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	configCache.Store(key, cachedConfig{modTime: info.ModTime(), size: info.Size(), config: config})
+	return config, nil
+}
+
+// readConfig reads and validates the configuration file at the given path.
+// The file is decoded as YAML if it has a ".yaml" or ".yml" extension, otherwise as JSON.
+func readConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read configuration: %v", err)
+	}
+
+	config := &Config{}
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(config)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration %q: %v", path, err)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration %q: %v", path, err)
+	}
+	return config, nil
+}
+
+// registryPath returns the path of the configured registry, resolved relative to the given configuration file.
+func (config *Config) registryPath(configFile string) string {
+	if config.Registry == "" || filepath.IsAbs(config.Registry) {
+		return config.Registry
+	}
+	return filepath.Join(filepath.Dir(configFile), config.Registry)
+}
+
+// validate checks the values of the configuration, which are not checked when decoding.
+func (config *Config) validate() error {
+	switch config.Generated {
+	case "", generatedReport, generatedIgnore:
+	default:
+		return fmt.Errorf("generated has to be %q or %q, but was %q", generatedReport, generatedIgnore, config.Generated)
+	}
+
+	if err := validateCategories(config.DisabledCategories); err != nil {
+		return err
+	}
+	if err := validateCodePrefix(config.CodePrefix); err != nil {
+		return err
+	}
+	if config.MaxCodes < 0 {
+		return fmt.Errorf("maxCodes must not be negative, but was %d", config.MaxCodes)
+	}
+
+	for _, boundary := range config.Boundaries {
+		if err := boundary.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, pkg := range config.Packages {
+		if pkg.Pattern == "" {
+			return fmt.Errorf("package entries require a pattern")
+		}
+		if _, err := regexp.Compile(pkg.CodePattern); err != nil {
+			return fmt.Errorf("invalid code pattern for packages %q: %v", pkg.Pattern, err)
+		}
+		if err := validateCategories(pkg.DisabledCategories); err != nil {
+			return err
+		}
+		if err := validateCodePrefix(pkg.CodePrefix); err != nil {
+			return fmt.Errorf("invalid code prefix for packages %q: %v", pkg.Pattern, err)
+		}
+		if pkg.MaxCodes != nil && *pkg.MaxCodes < 0 {
+			return fmt.Errorf("maxCodes for packages %q must not be negative, but was %d", pkg.Pattern, *pkg.MaxCodes)
+		}
+	}
+
+	return nil
+}
+
+func (boundary *BoundaryConfig) validate() error {
+	if len(boundary.Packages) == 0 || len(boundary.Codes) == 0 {
+		return fmt.Errorf("boundaries require packages and codes")
+	}
+	for _, pattern := range boundary.Codes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid code pattern %q of boundary: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+func validateCategories(names []string) error {
+	for _, name := range names {
+		if _, ok := categories[name]; !ok {
+			return fmt.Errorf("unknown diagnostic category %q", name)
+		}
+	}
+	return nil
+}
+
+// resolve computes the configuration for the package of the given pass.
+// The given settings are used, unless the configuration overrides them.
+func (config *Config) resolve(pass *analysis.Pass, settings *Settings) *packageConfig {
+	path := pass.Pkg.Path()
+	result := &packageConfig{
+		strict:             settings.Strict,
+		infer:              settings.Infer,
+		allowedExternal:    config.AllowedExternalPackages,
+		maxCodes:           config.MaxCodes,
+		disabledCategories: map[string]struct{}{},
+	}
+
+	if config.Strict != nil {
+		result.strict = *config.Strict
+	}
+	if config.Infer != nil {
+		result.infer = *config.Infer
+	}
+
+	for _, pattern := range config.Exclude {
+		if MatchPackagePattern(pattern, path) {
+			result.excluded = true
+		}
+	}
+
+	if config.Generated == generatedIgnore {
+		result.generatedFiles = map[*token.File]struct{}{}
+		for _, file := range pass.Files {
+			if isGeneratedFile(file) {
+				result.generatedFiles[pass.Fset.File(file.Pos())] = struct{}{}
+			}
+		}
+	}
+
+	for _, name := range config.DisabledCategories {
+		result.disabledCategories[name] = struct{}{}
+	}
+
+	for _, boundary := range config.Boundaries {
+		for _, pattern := range boundary.Packages {
+			if MatchPackagePattern(pattern, path) {
+				result.boundaries = append(result.boundaries, boundary)
+				break
+			}
+		}
+	}
+
+	codePrefix := config.CodePrefix
+
+	for _, pkg := range config.Packages {
+		if !MatchPackagePattern(pkg.Pattern, path) {
+			continue
+		}
+
+		if pkg.Strict != nil {
+			result.strict = *pkg.Strict
+		}
+		if pkg.Infer != nil {
+			result.infer = *pkg.Infer
+		}
+		if pkg.CodePattern != "" {
+			result.codePattern = regexp.MustCompile(pkg.CodePattern) // already validated
+		}
+		if pkg.CodePrefix != "" {
+			codePrefix = pkg.CodePrefix
+		}
+		if pkg.MaxCodes != nil {
+			result.maxCodes = *pkg.MaxCodes
+		}
+		for _, name := range pkg.DisabledCategories {
+			result.disabledCategories[name] = struct{}{}
+		}
+	}
+
+	if codePrefix != "" {
+		modulePath := ""
+		if len(pass.Files) > 0 {
+			modulePath = findModulePath(filepath.Dir(pass.Fset.File(pass.Files[0].Pos()).Name()))
+		}
+		result.codePrefix = expandCodePrefix(codePrefix, path, pass.Pkg.Name(), modulePath)
+	}
+
+	return result
+}
+
+// suppresses checks if the given diagnostic should not be reported.
+func (config *packageConfig) suppresses(pass *analysis.Pass, diagnostic analysis.Diagnostic) bool {
+	if config.excluded {
+		return true
+	}
+
+	if _, ok := config.disabledCategories[diagnostic.Category]; ok {
+		return true
+	}
+
+	if config.generatedFiles != nil {
+		if _, ok := config.generatedFiles[pass.Fset.File(diagnostic.Pos)]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isCalleeInAllowedPackage checks if the given function belongs to a package,
+// whose functions may be called without declaring error codes.
+func isCalleeInAllowedPackage(pass *analysis.Pass, callee types.Object) bool {
+	if callee == nil || callee.Pkg() == nil {
+		return false
+	}
+
+	for _, pattern := range getPackageConfig(pass).allowedExternal {
+		if MatchPackagePattern(pattern, callee.Pkg().Path()) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPackagePattern checks if the given import path matches the given pattern.
+//
+// Patterns follow the rules of the go command: "..." matches any string,
+// and a pattern ending in "/..." also matches the path without that suffix.
+func MatchPackagePattern(pattern, path string) bool {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.Replace(expr, `\.\.\.`, `.*`, -1)
+	if strings.HasSuffix(expr, `/.*`) {
+		expr = strings.TrimSuffix(expr, `/.*`) + `(/.*)?`
+	}
+	return regexp.MustCompile("^" + expr + "$").MatchString(path)
+}
+
+// isGeneratedFile checks if the given file is marked as generated,
+// using the convention described at https://golang.org/s/generatedcode.
+func isGeneratedFile(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			return false
+		}
+		for _, comment := range group.List {
+			if strings.HasPrefix(comment.Text, "// Code generated ") && strings.HasSuffix(comment.Text, " DO NOT EDIT.") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// codePrefixPlaceholder matches placeholders in code prefix templates.
+var codePrefixPlaceholder = regexp.MustCompile(`\{[^}]*\}`)
+
+func validateCodePrefix(template string) error {
+	for _, placeholder := range codePrefixPlaceholder.FindAllString(template, -1) {
+		switch placeholder {
+		case "{module}", "{package}", "{name}":
+		default:
+			return fmt.Errorf("unknown placeholder %q in code prefix %q", placeholder, template)
+		}
+	}
+	return nil
+}
+
+// expandCodePrefix replaces the placeholders in the given code prefix template for the given package.
+// The module path may be empty, if the module of the package is unknown.
+func expandCodePrefix(template, pkgPath, pkgName, modulePath string) string {
+	var module, relative string
+	switch {
+	case modulePath != "" && pkgPath == modulePath:
+		module = modulePath
+	case modulePath != "" && strings.HasPrefix(pkgPath, modulePath+"/"):
+		module, relative = modulePath, strings.TrimPrefix(pkgPath, modulePath+"/")
+	default:
+		// Unknown module: treat the first element of the import path as module.
+		parts := strings.SplitN(pkgPath, "/", 2)
+		module = parts[0]
+		if len(parts) == 2 {
+			relative = parts[1]
+		}
+	}
+
+	module = module[strings.LastIndex(module, "/")+1:]
+	if dot := strings.Index(module, "."); dot > 0 {
+		module = module[:dot]
+	}
+	if relative == "" {
+		relative = pkgName
+	}
+
+	replacer := strings.NewReplacer(
+		"{module}", codeSafe(module),
+		"{package}", codeSafe(relative),
+		"{name}", codeSafe(pkgName),
+	)
+	return replacer.Replace(template)
+}
+
+// codeSafe converts the given string into a part of an error code,
+// by replacing all characters that are not allowed in error codes with dashes.
+func codeSafe(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '-'
+	}, value)
+}
+
+// findModulePath searches the given directory and its parents for a "go.mod" file,
+// and returns the module path declared in it. If no module was found, the empty string is returned.
+func findModulePath(dir string) string {
+	for {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				fields := strings.Fields(line)
+				if len(fields) == 2 && fields[0] == "module" {
+					return strings.Trim(fields[1], `"`)
+				}
+			}
+			return ""
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// checkCreatedErrorCode checks a code, which originates in the current package,
+// against the naming rules and the registry configured for the package.
+func checkCreatedErrorCode(pass *analysis.Pass, rng analysis.Range, code string) {
+	checkErrorCodeNaming(pass, rng, code)
+	checkErrorCodeRegistered(pass, rng, code)
+}
+
+// checkErrorCodeNaming emits a diagnostic if the given code, which originates in the current package,
+// does not follow the naming rules configured for the package.
+func checkErrorCodeNaming(pass *analysis.Pass, rng analysis.Range, code string) {
+	config := getPackageConfig(pass)
+	if config.codePrefix != "" && !strings.HasPrefix(code, config.codePrefix) {
+		reportCodes(pass, categoryCodeNaming, rng, []string{code}, "error code %q does not start with the prefix %q required for codes of package %q", code, config.codePrefix, pass.Pkg.Path())
+	}
+	if config.codePattern != nil && !config.codePattern.MatchString(code) {
+		reportCodes(pass, categoryCodeNaming, rng, []string{code}, "error code %q does not match the pattern %q required for codes of package %q", code, config.codePattern, pass.Pkg.Path())
+	}
+}
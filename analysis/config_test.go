@@ -0,0 +1,147 @@
+package analysis
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatchPackagePattern(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		match         bool
+	}{
+		{"example.org/storage", "example.org/storage", true},
+		{"example.org/storage", "example.org/storage/inner", false},
+		{"example.org/storage/...", "example.org/storage", true},
+		{"example.org/storage/...", "example.org/storage/inner", true},
+		{"example.org/storage/...", "example.org/storagex", false},
+		{"example.org/.../internal", "example.org/a/b/internal", true},
+		{"example.org/.../internal", "example.org/a/b/internal/x", false},
+		{"...", "anything", true},
+	}
+
+	for _, test := range tests {
+		if MatchPackagePattern(test.pattern, test.path) != test.match {
+			t.Errorf("MatchPackagePattern(%q, %q) should return %v but did not", test.pattern, test.path, test.match)
+		}
+	}
+}
+
+func TestReadConfig(t *testing.T) {
+	tests := []struct {
+		name, content, err string
+	}{
+		{".serum.json", `{"strict": true, "packages": [{"pattern": "a/...", "codePattern": "^a-"}]}`, ""},
+		{".serum.yaml", "strict: true\npackages:\n  - pattern: a/...\n    codePattern: ^a-\n", ""},
+		{".serum.json", `{"infer": true, "packages": [{"pattern": "a/...", "infer": false}]}`, ""},
+		{".serum.json", `{"unknown": true}`, "unknown field"},
+		{".serum.yaml", "unknown: true\n", "not found"},
+		{".serum.json", `{"generated": "maybe"}`, `generated has to be "report" or "ignore"`},
+		{".serum.json", `{"disabledCategories": ["no-such-category"]}`, `unknown diagnostic category "no-such-category"`},
+		{".serum.json", `{"packages": [{"codePattern": "a"}]}`, "package entries require a pattern"},
+		{".serum.json", `{"packages": [{"pattern": "a", "codePattern": "("}]}`, "invalid code pattern"},
+		{".serum.json", `{"codePrefix": "{module}-{pkg}-"}`, `unknown placeholder "{pkg}"`},
+		{".serum.json", `{"packages": [{"pattern": "a", "codePrefix": "{Module}-"}]}`, "invalid code prefix"},
+		{".serum.yaml", "maxCodes: 8\npackages:\n  - pattern: a/...\n    maxCodes: 0\n", ""},
+		{".serum.json", `{"maxCodes": -1}`, "must not be negative"},
+		{".serum.yaml", "boundaries:\n  - packages: [a/api/...]\n    codes: [db-*]\n    reason: translate them\n", ""},
+		{".serum.json", `{"boundaries": [{"packages": ["a/api"]}]}`, "boundaries require packages and codes"},
+		{".serum.json", `{"boundaries": [{"packages": ["a/api"], "codes": ["db-["]}]}`, `invalid code pattern "db-[" of boundary`},
+	}
+
+	for _, test := range tests {
+		dir, err := ioutil.TempDir("", "serum-config")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, test.name)
+		if err := ioutil.WriteFile(path, []byte(test.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = readConfig(path)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("readConfig(%s) returned unexpected error: %v", test.content, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("readConfig(%s) should return error containing %q but returned: %v", test.content, test.err, err)
+		}
+	}
+}
+
+func TestExpandCodePrefix(t *testing.T) {
+	tests := []struct {
+		template, pkgPath, pkgName, modulePath string
+		expected                               string
+	}{
+		{"{module}-{package}-", "acme.io/storage", "storage", "acme.io", "acme-storage-"},
+		{"{module}-{package}-", "acme.io/storage/blob_store", "blobs", "acme.io", "acme-storage-blob-store-"},
+		{"{module}-{package}-", "acme.io", "acme", "acme.io", "acme-acme-"},
+		{"{module}-{name}-", "github.com/acme/storage/blob", "blob", "github.com/acme/storage", "storage-blob-"},
+		{"{module}-{package}-", "config/prefix", "prefix", "github.com/serum-errors/go-serum-analyzer", "config-prefix-"},
+		{"{module}-{package}-", "single", "single", "", "single-single-"},
+		{"fixed-", "acme.io/storage", "storage", "acme.io", "fixed-"},
+	}
+
+	for _, test := range tests {
+		actual := expandCodePrefix(test.template, test.pkgPath, test.pkgName, test.modulePath)
+		if actual != test.expected {
+			t.Errorf("expandCodePrefix(%q, %q, %q, %q) should return %q but returned %q",
+				test.template, test.pkgPath, test.pkgName, test.modulePath, test.expected, actual)
+		}
+	}
+}
+
+func TestFindConfigFile(t *testing.T) {
+	dir := filepath.Join("testdata", "src", "config", "lenient")
+	if found := findConfigFile(dir); found != filepath.Join("testdata", "src", "config", ".serum.json") {
+		t.Errorf("findConfigFile(%q) found %q", dir, found)
+	}
+
+	// The search stops at the module root of this repository.
+	dir = filepath.Join("testdata", "src", "001")
+	if found := findConfigFile(dir); found != "" {
+		t.Errorf("findConfigFile(%q) should not find a file, but found %q", dir, found)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "serum-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, ".serum.json")
+	if err := ioutil.WriteFile(path, []byte(`{"strict": true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	first, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("loadConfig(%q) should reuse the parsed configuration of an unchanged file", path)
+	}
+
+	// A changed file is read again.
+	if err := ioutil.WriteFile(path, []byte(`{"strict": false, "infer": true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed == first || changed.Infer == nil {
+		t.Errorf("loadConfig(%q) should read the changed file again", path)
+	}
+}
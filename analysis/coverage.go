@@ -0,0 +1,83 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Documentation states of error returning functions.
+const (
+	DocCodes        = "codes"        // the function declares error codes or an error code parameter
+	DocNone         = "none"         // the function declares that it returns no error codes
+	DocUndocumented = "undocumented" // the function does not declare error codes, or its docs are malformed
+)
+
+// FunctionDoc is the documentation state of an error returning function, method or interface method.
+type FunctionDoc struct {
+	Func  *types.Func
+	State string // one of DocCodes, DocNone or DocUndocumented
+}
+
+// findDocCoverage determines the documentation state of the given error returning functions,
+// and of all error returning interface methods of the package.
+//
+// Cause() methods of error types are left out, because they never have to declare error codes.
+func findDocCoverage(pass *analysis.Pass, funcsToAnalyse []*ast.FuncDecl) []FunctionDoc {
+	var result []FunctionDoc
+	for _, funcDecl := range funcsToAnalyse {
+		if isMethod(funcDecl) && funcDecl.Name.Name == "Cause" {
+			receiverType := pass.TypesInfo.TypeOf(funcDecl.Recv.List[0].Type)
+			if types.Implements(receiverType, tReeErrorWithCause) {
+				continue
+			}
+		}
+
+		if fn, ok := pass.TypesInfo.Defs[funcDecl.Name].(*types.Func); ok {
+			result = append(result, FunctionDoc{fn, docState(funcDecl.Doc)})
+		}
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			interfaceType, ok := node.(*ast.InterfaceType)
+			if !ok || interfaceType.Methods == nil {
+				return true
+			}
+
+			for _, method := range interfaceType.Methods.List {
+				funcType, ok := method.Type.(*ast.FuncType)
+				if !ok || len(method.Names) == 0 || !returnsError(pass, funcType) {
+					continue
+				}
+
+				if method.Names[0].Name == "Cause" && types.Implements(pass.TypesInfo.TypeOf(interfaceType), tReeErrorWithCause) {
+					continue
+				}
+
+				if fn, ok := pass.TypesInfo.Defs[method.Names[0]].(*types.Func); ok {
+					result = append(result, FunctionDoc{fn, docState(method.Doc)})
+				}
+			}
+			return true
+		})
+	}
+
+	return result
+}
+
+// docState determines the documentation state of a function with the given docs.
+func docState(doc *ast.CommentGroup) string {
+	docs, err := findErrorDocs(doc)
+	switch {
+	case err != nil:
+		return DocUndocumented
+	case len(docs.Codes) > 0 || docs.Param != "":
+		return DocCodes
+	case docs.NoCodes:
+		return DocNone
+	default:
+		return DocUndocumented
+	}
+}
@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"sort"
+)
+
+// checkFunctionDeprecatedCodes emits a diagnostic for each deprecated error code, that the given function starts to return.
+//
+// Functions may pass on deprecated codes returned by called functions,
+// and may create deprecated codes if their own docs declare the codes as deprecated,
+// which is how the existing origins of a deprecated code are marked.
+// Every other function creating a deprecated code is reported.
+func checkFunctionDeprecatedCodes(c *context, funcDecl *ast.FuncDecl, claims funcCodes) {
+	codes := claims.codes.Slice()
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		attributes := c.attributes[code]
+		if !attributes.Deprecated || claims.attributes[code].Deprecated {
+			continue
+		}
+		if len(findCalleeCodes(c, funcDecl, Set(code))) > 0 {
+			continue
+		}
+
+		reportCodes(c.pass, categoryDeprecatedCode, funcDecl.Name, []string{code},
+			"function %q returns deprecated error code %q%s", funcDecl.Name.Name, code, describeReplacement(attributes))
+	}
+}
+
+// findDeprecatedCodeComparisons emits a diagnostic for each comparison with a deprecated error code
+// in the conditions of if statements and the cases of switch statements.
+func findDeprecatedCodeComparisons(c *context) {
+	for _, file := range c.pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.IfStmt:
+				checkDeprecatedComparisons(c, node.Cond)
+			case *ast.SwitchStmt:
+				for _, stmt := range node.Body.List {
+					for _, expr := range stmt.(*ast.CaseClause).List {
+						if node.Tag != nil {
+							if codeRetrievalTarget(node.Tag) != nil {
+								checkDeprecatedCodeConstant(c, expr)
+							}
+						} else {
+							checkDeprecatedComparisons(c, expr)
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// checkDeprecatedComparisons checks all == and != comparisons of retrieved error codes in the given condition.
+// Comparisons of other strings are not checked, even if they are equal to a deprecated code.
+func checkDeprecatedComparisons(c *context, cond ast.Expr) {
+	ast.Inspect(cond, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false // the body is visited on its own
+		case *ast.BinaryExpr:
+			if node.Op != token.EQL && node.Op != token.NEQ {
+				break
+			}
+			if codeRetrievalTarget(node.X) != nil {
+				checkDeprecatedCodeConstant(c, node.Y)
+			}
+			if codeRetrievalTarget(node.Y) != nil {
+				checkDeprecatedCodeConstant(c, node.X)
+			}
+		}
+		return true
+	})
+}
+
+// checkDeprecatedCodeConstant emits a diagnostic if the given expression is a constant string, that is a deprecated error code.
+func checkDeprecatedCodeConstant(c *context, expr ast.Expr) {
+	value := c.pass.TypesInfo.Types[expr].Value
+	if value == nil || value.Kind() != constant.String {
+		return
+	}
+
+	code := constant.StringVal(value)
+	if attributes := c.attributes[code]; attributes.Deprecated {
+		reportCodes(c.pass, categoryDeprecatedCheck, expr, []string{code},
+			"comparison with deprecated error code %q%s", code, describeReplacement(attributes))
+	}
+}
+
+// describeReplacement returns a hint to use the replacement of a deprecated code, or the empty string if there is none.
+func describeReplacement(attributes CodeAttributes) string {
+	if attributes.Replacement == "" {
+		return ""
+	}
+	return fmt.Sprintf(", use %q instead", attributes.Replacement)
+}
@@ -0,0 +1,172 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// findDiscardedErrors emits diagnostics for calls to functions declaring error codes, whose error result is discarded:
+// calls used as expression statements or in go and defer statements, error results assigned to the blank identifier,
+// and error variables that are overwritten before they are checked.
+//
+// Functions declaring "Errors: none" are not reported, because there are no codes to lose.
+func findDiscardedErrors(c *context) {
+	for _, file := range c.pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.ExprStmt:
+				if callExpr, ok := astutil.Unparen(node.X).(*ast.CallExpr); ok {
+					reportDiscardedError(c, callExpr, "is discarded")
+				}
+			case *ast.GoStmt:
+				reportDiscardedError(c, node.Call, "is discarded by the go statement")
+			case *ast.DeferStmt:
+				reportDiscardedError(c, node.Call, "is discarded by the defer statement")
+			case *ast.AssignStmt:
+				for _, assignment := range findErrorAssignments(c, node) {
+					if assignment.lhs.Name == "_" {
+						reportDiscardedError(c, assignment.call, "is assigned to the blank identifier")
+					}
+				}
+			case *ast.BlockStmt:
+				findOverwrittenErrors(c, node.List)
+			case *ast.CaseClause:
+				findOverwrittenErrors(c, node.Body)
+			case *ast.CommClause:
+				findOverwrittenErrors(c, node.Body)
+			}
+			return true
+		})
+	}
+}
+
+// errorAssignment is the assignment of the error result of a call to a single identifier.
+type errorAssignment struct {
+	lhs  *ast.Ident
+	call *ast.CallExpr
+}
+
+// findErrorAssignments finds the identifiers the error results of calls are assigned to in the given assignment,
+// for calls to functions declaring error codes. The error is assumed to be the last result of a call.
+func findErrorAssignments(c *context, assignStmt *ast.AssignStmt) []errorAssignment {
+	var result []errorAssignment
+	add := func(lhs ast.Expr, rhs ast.Expr) {
+		ident, ok := astutil.Unparen(lhs).(*ast.Ident)
+		if !ok {
+			return
+		}
+		callExpr, ok := astutil.Unparen(rhs).(*ast.CallExpr)
+		if !ok || len(declaredCodesOfCall(c, callExpr)) == 0 {
+			return
+		}
+		result = append(result, errorAssignment{ident, callExpr})
+	}
+
+	switch {
+	case len(assignStmt.Rhs) == 1 && len(assignStmt.Lhs) > 1: // x, err := f()
+		add(assignStmt.Lhs[len(assignStmt.Lhs)-1], assignStmt.Rhs[0])
+	case len(assignStmt.Lhs) == len(assignStmt.Rhs): // err := f() or a, b := f(), g()
+		for i := range assignStmt.Lhs {
+			add(assignStmt.Lhs[i], assignStmt.Rhs[i])
+		}
+	}
+	return result
+}
+
+// findOverwrittenErrors emits a diagnostic for each local error variable in the given statements,
+// that is assigned the error result of a call and then overwritten by a following statement, without being read in between.
+//
+// Only the statements of a single list are looked at: if a following statement is a compound statement referring to the variable,
+// the variable is assumed to be checked.
+func findOverwrittenErrors(c *context, stmts []ast.Stmt) {
+	for i, stmt := range stmts {
+		assignStmt, ok := stmt.(*ast.AssignStmt)
+		if !ok {
+			continue
+		}
+
+		for _, assignment := range findErrorAssignments(c, assignStmt) {
+			variable, ok := c.pass.TypesInfo.ObjectOf(assignment.lhs).(*types.Var)
+			if !ok || variable.Parent() == nil || variable.Parent() == c.pass.Pkg.Scope() {
+				continue // not a local variable
+			}
+
+			if isOverwrittenBeforeRead(c, variable, stmts[i+1:]) {
+				reportDiscardedError(c, assignment.call, "is overwritten before it is checked")
+			}
+		}
+	}
+}
+
+// isOverwrittenBeforeRead returns true, if the first of the given statements referring to the given variable
+// assigns a new value to it without reading it.
+func isOverwrittenBeforeRead(c *context, variable *types.Var, stmts []ast.Stmt) bool {
+	for _, stmt := range stmts {
+		assignStmt, ok := stmt.(*ast.AssignStmt)
+		if !ok || assignStmt.Tok != token.ASSIGN && assignStmt.Tok != token.DEFINE {
+			if refersTo(c, stmt, variable) {
+				return false
+			}
+			continue
+		}
+
+		for _, rhs := range assignStmt.Rhs {
+			if refersTo(c, rhs, variable) {
+				return false
+			}
+		}
+		for _, lhs := range assignStmt.Lhs {
+			if ident, ok := astutil.Unparen(lhs).(*ast.Ident); ok && c.pass.TypesInfo.ObjectOf(ident) == variable {
+				return true
+			} else if refersTo(c, lhs, variable) {
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// refersTo returns true, if the given node contains an identifier referring to the given variable.
+func refersTo(c *context, node ast.Node, variable *types.Var) bool {
+	found := false
+	ast.Inspect(node, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok && c.pass.TypesInfo.Uses[ident] == variable {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// declaredCodesOfCall returns the codes of the ErrorCodes fact of the function called by the given call,
+// or nil if there is no fact for the called function.
+func declaredCodesOfCall(c *context, callExpr *ast.CallExpr) CodeSet {
+	callee, ok := typeutil.Callee(c.pass.TypesInfo, callExpr).(*types.Func)
+	if !ok {
+		return nil
+	}
+
+	var fact ErrorCodes
+	if !c.pass.ImportObjectFact(callee, &fact) {
+		return nil
+	}
+	return fact.Codes
+}
+
+func reportDiscardedError(c *context, callExpr *ast.CallExpr, what string) {
+	codes := declaredCodesOfCall(c, callExpr).Slice()
+	if len(codes) == 0 {
+		return
+	}
+	sort.Strings(codes)
+
+	callee := typeutil.Callee(c.pass.TypesInfo, callExpr).(*types.Func)
+	reportCodes(c.pass, categoryDiscardedError, callExpr, codes, "error result of %s %s, dropping error codes: %s",
+		calleeName(c.pass, callee), what, strings.Join(codes, " "))
+}
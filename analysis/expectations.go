@@ -0,0 +1,237 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// serumtestPath is the import path of the package providing the ExpectCode test helper.
+const serumtestPath = "github.com/serum-errors/go-serum-analyzer/serumtest"
+
+// CodeExpectation is a call of serumtest.ExpectCode, expecting an error code of the functions under test.
+type CodeExpectation struct {
+	// Code is the expected error code.
+	Code string
+	// Funcs are the functions the checked error may come from.
+	Funcs []*types.Func
+}
+
+// findCodeExpectations finds all calls of serumtest.ExpectCode in the current package,
+// where the checked error comes from calls of functions declaring error codes.
+// A diagnostic is emitted for each expected code, that is not declared by any of those functions.
+//
+// The checked error may be a call itself, or a local variable assigned from such calls.
+// Expectations of errors from other sources are ignored.
+//
+// The expected code is either a constant, or a field of the cases of a table-driven test (see findTableCodes).
+func findCodeExpectations(c *context) []CodeExpectation {
+	pass := c.pass
+	var sources map[*types.Var]*errorSources
+	var result []CodeExpectation
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			callExpr, ok := node.(*ast.CallExpr)
+			if !ok || len(callExpr.Args) != 3 || !isExpectCode(typeutil.Callee(pass.TypesInfo, callExpr)) {
+				return true
+			}
+			codes := findExpectedCodes(pass.TypesInfo, file, callExpr.Args[2])
+			if len(codes) == 0 {
+				return true
+			}
+
+			var source *errorSources
+			switch errExpr := astutil.Unparen(callExpr.Args[1]).(type) {
+			case *ast.CallExpr:
+				callee, ok := typeutil.Callee(pass.TypesInfo, errExpr).(*types.Func)
+				var fact ErrorCodes
+				if ok && pass.ImportObjectFact(callee, &fact) {
+					source = &errorSources{codes: fact.Codes, callees: []*types.Func{callee}}
+				}
+			case *ast.Ident:
+				if sources == nil {
+					sources = findErrorSources(c)
+				}
+				variable, _ := pass.TypesInfo.Uses[errExpr].(*types.Var)
+				source = sources[variable]
+			}
+			if source == nil || source.unknown || len(source.callees) == 0 {
+				return true
+			}
+
+			for _, code := range codes {
+				if _, ok := source.codes[code.code]; !ok {
+					reportUnexpectedCode(c, code.expr, code.code, source)
+				}
+				result = append(result, CodeExpectation{code.code, append([]*types.Func(nil), source.callees...)})
+			}
+			return true
+		})
+	}
+
+	return result
+}
+
+// expectedCode is an expected error code, together with the expression defining it.
+type expectedCode struct {
+	code string
+	expr ast.Expr
+}
+
+// findExpectedCodes returns the codes the given expression may evaluate to.
+// The expression is either a constant string, or a field of the cases of a table-driven test (see findTableCodes).
+func findExpectedCodes(info *types.Info, file *ast.File, expr ast.Expr) []expectedCode {
+	if code, ok := constantCode(info, expr); ok {
+		return []expectedCode{{code, expr}}
+	}
+
+	selector, ok := astutil.Unparen(expr).(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	ident, ok := astutil.Unparen(selector.X).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	testCase, ok := info.Uses[ident].(*types.Var)
+	if !ok {
+		return nil
+	}
+	return findTableCodes(info, file, expr, testCase, selector.Sel.Name)
+}
+
+// findTableCodes finds the constant values of the given field in the cases of a table-driven test:
+//
+//	tests := []struct{ code string }{{code: "pkg-not-found"}, {code: "pkg-timeout"}}
+//	for _, test := range tests {
+//		...
+//		serumtest.ExpectCode(t, err, test.code)
+//	}
+//
+// The test case is the value variable of a range statement enclosing the given expression.
+// The table is either ranged over directly, or a local variable assigned a composite literal.
+// Cases without a constant value of the field are ignored.
+func findTableCodes(info *types.Info, file *ast.File, expr ast.Expr, testCase *types.Var, field string) []expectedCode {
+	path, _ := astutil.PathEnclosingInterval(file, expr.Pos(), expr.End())
+	var table *ast.CompositeLit
+	for i, node := range path {
+		if _, ok := node.(*ast.FuncDecl); ok {
+			break
+		}
+		rangeStmt, ok := node.(*ast.RangeStmt)
+		if !ok || rangeStmt.Value == nil {
+			continue
+		}
+		if value, ok := rangeStmt.Value.(*ast.Ident); !ok || info.Defs[value] != testCase {
+			continue
+		}
+
+		table = findTable(info, path[i+1:], rangeStmt.X)
+		break
+	}
+	if table == nil {
+		return nil
+	}
+
+	var result []expectedCode
+	for _, element := range table.Elts {
+		if valueExpr := findFieldValue(info, element, field); valueExpr != nil {
+			if code, ok := constantCode(info, valueExpr); ok {
+				result = append(result, expectedCode{code, valueExpr})
+			}
+		}
+	}
+	return result
+}
+
+// findTable returns the composite literal of the given ranged over expression.
+// If the expression is a variable, its definition is searched in the given enclosing nodes.
+func findTable(info *types.Info, enclosing []ast.Node, expr ast.Expr) *ast.CompositeLit {
+	switch expr := astutil.Unparen(expr).(type) {
+	case *ast.CompositeLit:
+		return expr
+	case *ast.Ident:
+		variable := info.Uses[expr]
+		if variable == nil {
+			return nil
+		}
+		for _, node := range enclosing {
+			block, ok := node.(*ast.BlockStmt)
+			if !ok {
+				continue
+			}
+			for _, stmt := range block.List {
+				assignStmt, ok := stmt.(*ast.AssignStmt)
+				if !ok || assignStmt.Tok != token.DEFINE || len(assignStmt.Lhs) != len(assignStmt.Rhs) {
+					continue
+				}
+				for i, lhs := range assignStmt.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && info.Defs[ident] == variable {
+						table, _ := astutil.Unparen(assignStmt.Rhs[i]).(*ast.CompositeLit)
+						return table
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// findFieldValue returns the value of the given field in the given composite literal of a struct,
+// or nil if the field is not set.
+func findFieldValue(info *types.Info, element ast.Expr, field string) ast.Expr {
+	compositeLit, ok := astutil.Unparen(element).(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	structType, ok := info.TypeOf(compositeLit).Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	for i, fieldExpr := range compositeLit.Elts {
+		if keyValue, ok := fieldExpr.(*ast.KeyValueExpr); ok {
+			if key, ok := keyValue.Key.(*ast.Ident); ok && key.Name == field {
+				return keyValue.Value
+			}
+		} else if i < structType.NumFields() && structType.Field(i).Name() == field {
+			return fieldExpr
+		}
+	}
+	return nil
+}
+
+// constantCode returns the value of the given expression, if it is a constant string.
+func constantCode(info *types.Info, expr ast.Expr) (string, bool) {
+	value := info.Types[expr].Value
+	if value == nil || value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(value), true
+}
+
+// isExpectCode checks if the given object is the ExpectCode function of the serumtest package.
+func isExpectCode(object types.Object) bool {
+	fn, ok := object.(*types.Func)
+	return ok && fn.Name() == "ExpectCode" && fn.Pkg() != nil && fn.Pkg().Path() == serumtestPath
+}
+
+func reportUnexpectedCode(c *context, rng ast.Node, code string, source *errorSources) {
+	callees := calleeNames(c.pass, source.callees)
+	verb := "declares"
+	if len(callees) > 1 {
+		verb = "declare"
+	}
+
+	codes := source.codes.Slice()
+	sort.Strings(codes)
+	reportCodes(c.pass, categoryTestExpectation, rng, []string{code}, "test expects error code %q, but %s only %s %v",
+		code, strings.Join(callees, ", "), verb, codes)
+}
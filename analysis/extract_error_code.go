@@ -18,13 +18,13 @@ func extractErrorCodesFromAffector(pass *analysis.Pass, lookup *funcLookup, func
 
 	// Make sure method "Code() string" is present
 	if !checkErrorTypeHasLegibleCode(pass, affector) {
-		pass.ReportRangef(affector, "expression does not define an error code")
+		reportRange(pass, categoryErrorType, affector, "expression does not define an error code")
 		return result
 	}
 
 	errorType, err := getErrorTypeForError(pass, pass.TypesInfo.Types[affector].Type)
 	if err != nil || errorType == nil {
-		pass.ReportRangef(affector, "expression is not a valid error: error types must return constant error codes or a single field")
+		reportRange(pass, categoryErrorType, affector, "expression is not a valid error: error types must return constant error codes or a single field")
 	}
 	if err != nil {
 		logf("Error while looking at affector: %v (Affector: %#v)\n", err, affector)
@@ -39,6 +39,10 @@ func extractErrorCodesFromAffector(pass *analysis.Pass, lookup *funcLookup, func
 				result.Add(code)
 			}
 		}
+
+		if named := getNamedType(pass.TypesInfo.TypeOf(affector)); named != nil && len(result) > 0 {
+			lookup.addCodeSource(function, codeSource{named.Obj(), nil, result})
+		}
 	}
 
 	return result
@@ -109,7 +113,7 @@ func findFieldInitExpression(pass *analysis.Pass, constructExpr ast.Expr, field
 		logf("findFieldInitExpression did not yet handle: %#v\n", expr)
 	}
 
-	pass.ReportRangef(constructExpr, "could not find initialiser for error code field in contructor expression")
+	reportRange(pass, categoryUnsupported, constructExpr, "could not find initialiser for error code field in contructor expression")
 	return nil
 }
 
@@ -128,14 +132,14 @@ func extractErrorCodesFromTypeConversion(pass *analysis.Pass, callExpr *ast.Call
 
 // extractErrorCodeFromConstructorCall checks if the given callee is an error constructor and
 // then extracts the error code from the correct call argument.
-func extractErrorCodeFromConstructorCall(pass *analysis.Pass, startingFunc *funcDefinition, reportRange analysis.Range, callee types.Object, callExpr *ast.CallExpr) (string, bool) {
+func extractErrorCodeFromConstructorCall(pass *analysis.Pass, startingFunc *funcDefinition, reportPos analysis.Range, callee types.Object, callExpr *ast.CallExpr) (string, bool) {
 	var fact ErrorConstructor
 	if callee == nil || !pass.ImportObjectFact(callee, &fact) {
 		return "", false
 	}
 
 	if callExpr == nil {
-		pass.ReportRangef(reportRange, "unsupported use of error constructor %q", callee.Name())
+		reportRange(pass, categoryErrorConstructor, reportPos, "unsupported use of error constructor %q", callee.Name())
 		return "", false
 	}
 
@@ -151,7 +155,9 @@ func extractErrorCodeFromStringExpression(pass *analysis.Pass, function *funcDef
 	if ok && info.Value != nil {
 		code, err := getErrorCodeFromConstant(info.Value)
 		if err != nil {
-			pass.ReportRangef(codeExpr, "%v", err)
+			reportRange(pass, categoryInvalidCode, codeExpr, "%v", err)
+		} else if code != "" {
+			checkCreatedErrorCode(pass, codeExpr, code)
 		}
 		return code, err == nil && code != ""
 	}
@@ -166,7 +172,7 @@ func extractErrorCodeFromStringExpression(pass *analysis.Pass, function *funcDef
 	if paramPosition >= 0 {
 		checkIfExprIsErrorCodeParam(pass, function, &funcCodeParam{fieldExprIdent, paramPosition})
 	} else {
-		pass.ReportRangef(codeExpr, "error code has to be constant value or error code parameter")
+		reportRange(pass, categoryInvalidCode, codeExpr, "error code has to be constant value or error code parameter")
 	}
 
 	return "", false
@@ -209,7 +215,7 @@ func checkIfExprIsErrorCodeParam(pass *analysis.Pass, function *funcDefinition,
 	}()
 
 	if !ok {
-		pass.ReportRangef(param.ident, "require an error code parameter declaration to use %q as an error code", param.ident.Name)
+		reportRange(pass, categoryErrorConstructor, param.ident, "require an error code parameter declaration to use %q as an error code", param.ident.Name)
 	}
 }
 
@@ -238,11 +244,11 @@ func ectractErrorCodesFromConstructor(c *context, function *funcDefinition) Code
 	taintResult := taintSpreadForParamIdentOfImmutableType(pass, paramIdent, function)
 
 	for _, badIdent := range taintResult.identOutOfScope {
-		pass.ReportRangef(badIdent, "error code parameter may not be assigned an other parameter, receiver or global variable")
+		reportRange(pass, categoryErrorConstructor, badIdent, "error code parameter may not be assigned an other parameter, receiver or global variable")
 	}
 
 	for _, destruct := range taintResult.destructAssignment {
-		pass.ReportRangef(destruct.source, "unsupported: assigning result of function call to error code parameter %q is not allowed", destruct.target.Name)
+		reportRange(pass, categoryUnsupported, destruct.source, "unsupported: assigning result of function call to error code parameter %q is not allowed", destruct.target.Name)
 	}
 
 	for _, expr := range taintResult.expressions {
@@ -3,6 +3,7 @@ package analysis
 import (
 	"fmt"
 	"strings"
+	"unicode"
 )
 
 type state interface {
@@ -20,29 +21,97 @@ type state interface {
 //   - a line that is exactly "Errors:" starts a declaration block.
 //   - exactly one blank line must follow, or it's a bad format.
 //   - the next line must match "^- (.*) --", and the captured group is an error code.
-//     - note that this is after leading whitespace strip. (probably you should indent these, for readability.)
-//     - for simplier parsing, any line that starts with "- " will be slurped,
-//       and we'll consider it an error if the rest of the pattern doesn't follow.
-//     - the capture group can be stripped for whitespace again. (perhaps the author wanted to align things.)
-//     - the error code has to be valid, which means it has to match against: "^[a-zA-Z][a-zA-Z0-9\-]*[a-zA-Z0-9]$" or "^[a-zA-Z]$"
+//     note that this is after leading whitespace strip. (probably you should indent these, for readability.)
+//     for simplier parsing, any line that starts with "- " will be slurped,
+//     and we'll consider it an error if the rest of the pattern doesn't follow.
+//     the capture group can be stripped for whitespace again. (perhaps the author wanted to align things.)
+//     the error code has to be valid, which means it has to match against: "^[a-zA-Z][a-zA-Z0-9\-]*[a-zA-Z0-9]$" or "^[a-zA-Z]$"
+//   - the error code may be preceded by attributes in parentheses, e.g. "- (internal) pkg-error-retry --". (See CodeAttributes.)
+//     a comment starting with "(deprecated)" or "(deprecated: use <code>)" marks the code as deprecated.
 //   - for error constructors lines like "^- param: (.*) --" are allowed.
-//     - the captured group has to be a parameter of type string
+//     the captured group has to be a parameter of type string
 //   - this may repeat. if lines do not start that that pattern, they are skipped.
-//      - note that the same code may appear multiple times. this is acceptable, and should be deduplicated.
+//     note that the same code may appear multiple times. this is acceptable, and should be deduplicated.
 //   - when there's another fully blank line, the parse is ended.
 //   - instead of the standard decleration block it is also permitted to declare that a function does not return codes.
-//      - such a line must match "Errors: none(.*)".
-//      - this allows documentation; for example: "Errors: none -- this method only returns error to comply with the foobar interface."
+//     such a line must match "Errors: none(.*)".
+//     this allows documentation; for example: "Errors: none -- this method only returns error to comply with the foobar interface."
+//
 // This format happens to be amenable to letting you write the closest thing godocs have to a list.
 // (You should probably indent things "enough" to make that render right, but we're not checking that here right now.)
 //
-// If there are no error declarations, (nil, nil) is returned.
+// If there are no error declarations, the result has no codes, and NoCodes is false.
 // If there's what looks like an error declaration, but funny looking, an error is returned.
 type findErrorDocsSM struct {
-	seen      CodeSet
-	state     state
-	noCodesOk bool
-	param     string
+	seen             CodeSet
+	state            state
+	noCodesOk        bool
+	param            string
+	descriptions     map[string]string
+	attributes       map[string]CodeAttributes
+	paramDescription string
+}
+
+// CodeAttributes are the attributes of a declared error code,
+// given in parentheses in front of the code (e.g. "- (internal) pkg-error-retry --").
+type CodeAttributes struct {
+	// Internal is set for codes declared as "(internal)".
+	// Internal codes must not escape through exported functions of public packages.
+	Internal bool
+
+	// Deprecated is set for codes with a comment starting with "(deprecated)" or "(deprecated: use <code>)",
+	// or codes with the stability "deprecated" in the registry.
+	Deprecated bool
+
+	// Replacement is the code to use instead of a deprecated code, if any.
+	Replacement string
+}
+
+// merge returns the union of both attributes.
+func (a CodeAttributes) merge(other CodeAttributes) CodeAttributes {
+	replacement := a.Replacement
+	if replacement == "" {
+		replacement = other.Replacement
+	}
+	return CodeAttributes{
+		Internal:    a.Internal || other.Internal,
+		Deprecated:  a.Deprecated || other.Deprecated,
+		Replacement: replacement,
+	}
+}
+
+// prefix returns the attributes in the format used in front of a code in declarations, e.g. "(internal) ".
+// Deprecation is included as "(deprecated) ", even though it is declared in the comment of a code.
+func (a CodeAttributes) prefix() string {
+	var result string
+	if a.Internal {
+		result += "(internal) "
+	}
+	if a.Deprecated {
+		result += "(deprecated) "
+	}
+	return result
+}
+
+// ErrorDocs contains the error code declarations found in a docstring.
+type ErrorDocs struct {
+	Codes            CodeSet
+	Descriptions     map[string]string         // the description following the "--" of each declared code, if any
+	Attributes       map[string]CodeAttributes // the attributes of declared codes, only for codes having any
+	Param            string                    // name of the error code parameter of an error constructor, or empty
+	ParamDescription string
+	NoCodes          bool // true if "Errors: none" was declared
+}
+
+// ParseErrorDocs parses the error code declarations in the given docstring,
+// using the same format as the analyzer. (See findErrorDocsSM for the format.)
+func ParseErrorDocs(doc string) (*ErrorDocs, error) {
+	sm := &findErrorDocsSM{}
+	codes, param, noCodesOk, err := sm.run(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &ErrorDocs{codes, sm.descriptions, sm.attributes, param, sm.paramDescription, noCodesOk}, nil
 }
 
 // run runs the state machine to find error codes in the provided doc string.
@@ -50,15 +119,18 @@ type findErrorDocsSM struct {
 // The method returns a set of found codes,
 // a bool which is true if the function declared "Errors: none",
 // an error in case of invalid doc strings or nil otherwise.
-func (sm findErrorDocsSM) run(doc string) (CodeSet, string, bool, error) {
+func (sm *findErrorDocsSM) run(doc string) (CodeSet, string, bool, error) {
 	sm.seen = CodeSet{}
 	sm.state = stateInit{}
 	sm.noCodesOk = false
 	sm.param = ""
+	sm.descriptions = map[string]string{}
+	sm.attributes = nil
+	sm.paramDescription = ""
 
 	for _, line := range strings.Split(doc, "\n") {
 		line := strings.TrimSpace(line)
-		err := sm.state.step(&sm, line)
+		err := sm.state.step(sm, line)
 		if err != nil {
 			return nil, "", false, err
 		}
@@ -100,19 +172,11 @@ func (stateParsing) step(sm *findErrorDocsSM, line string) error {
 	case strings.HasPrefix(line, "Errors:"):
 		return fmt.Errorf("repeated 'Errors:' block indicator")
 	case strings.HasPrefix(line, "- "):
-		end := strings.Index(line, " --")
-		if end == -1 {
-			return fmt.Errorf("mid block, a line leading with '- ' didnt contain a '--' to mark the end of the code name")
-		}
-
-		if end < 2 {
-			return fmt.Errorf("an error code can't be purely whitespace")
-		}
-		code := line[2:end]
-		code = strings.TrimSpace(code)
-		if code == "" {
-			return fmt.Errorf("an error code can't be purely whitespace")
+		declaration, err := parseDeclarationLine(line)
+		if err != nil {
+			return err
 		}
+		code, description, attributes := declaration.code, declaration.description, declaration.attributes
 
 		if strings.HasPrefix(code, "param:") {
 			param := code[len("param:"):]
@@ -124,6 +188,7 @@ func (stateParsing) step(sm *findErrorDocsSM, line string) error {
 				return fmt.Errorf("cannot define more than one error code parameter (found multiple 'param:' inidicators)")
 			default:
 				sm.param = param
+				sm.paramDescription = description
 				return nil
 			}
 		}
@@ -135,10 +200,105 @@ func (stateParsing) step(sm *findErrorDocsSM, line string) error {
 		if _, exists := sm.seen[code]; !exists {
 			sm.seen[code] = struct{}{}
 		}
+		if sm.descriptions[code] == "" {
+			sm.descriptions[code] = description
+		}
+		if attributes != (CodeAttributes{}) {
+			if sm.attributes == nil {
+				sm.attributes = map[string]CodeAttributes{}
+			}
+			sm.attributes[code] = sm.attributes[code].merge(attributes)
+		}
 	}
 	return nil
 }
 
+// declarationLine is a line of an error code block, declaring an error code or an error code parameter.
+type declarationLine struct {
+	code        string // the declared code, or "param:" followed by the name of the parameter
+	offset      int    // offset of the code in the line
+	description string
+	attributes  CodeAttributes
+}
+
+// parseDeclarationLine parses a trimmed line of an error code block starting with "- ".
+func parseDeclarationLine(line string) (declarationLine, error) {
+	end := strings.Index(line, " --")
+	if end == -1 {
+		return declarationLine{}, fmt.Errorf("mid block, a line leading with '- ' didnt contain a '--' to mark the end of the code name")
+	}
+
+	if end < 2 {
+		return declarationLine{}, fmt.Errorf("an error code can't be purely whitespace")
+	}
+	code := strings.TrimSpace(line[2:end])
+	description := strings.TrimSpace(line[end+len(" --"):])
+	attributes, code, err := parseCodeAttributes(code)
+	if err != nil {
+		return declarationLine{}, err
+	}
+	if attributes.Deprecated, attributes.Replacement, err = parseDeprecation(description); err != nil {
+		return declarationLine{}, err
+	}
+	if code == "" {
+		return declarationLine{}, fmt.Errorf("an error code can't be purely whitespace")
+	}
+
+	// The code is the end of the trimmed text between "- " and " --".
+	offset := 2 + len(strings.TrimRightFunc(line[2:end], unicode.IsSpace)) - len(code)
+	return declarationLine{code, offset, description, attributes}, nil
+}
+
+// parseCodeAttributes parses the attributes in parentheses in front of a declared code,
+// and returns them together with the remaining code.
+func parseCodeAttributes(code string) (CodeAttributes, string, error) {
+	var attributes CodeAttributes
+	for strings.HasPrefix(code, "(") {
+		end := strings.Index(code, ")")
+		if end == -1 {
+			return attributes, "", fmt.Errorf("an error code attribute is missing the closing ')'")
+		}
+
+		switch attribute := strings.TrimSpace(code[1:end]); attribute {
+		case "internal":
+			attributes.Internal = true
+		default:
+			return attributes, "", fmt.Errorf("unknown error code attribute %q", attribute)
+		}
+		code = strings.TrimSpace(code[end+1:])
+	}
+	return attributes, code, nil
+}
+
+// parseDeprecation parses the deprecation marker at the start of the comment of a declared code,
+// which is either "(deprecated)" or "(deprecated: use <code>)".
+// It returns true and the replacement code (if any) if the comment has a deprecation marker.
+func parseDeprecation(description string) (bool, string, error) {
+	if !strings.HasPrefix(description, "(deprecated") {
+		return false, "", nil
+	}
+
+	end := strings.Index(description, ")")
+	if end == -1 {
+		return false, "", fmt.Errorf("a deprecation is missing the closing ')'")
+	}
+
+	rest := strings.TrimSpace(description[len("(deprecated"):end])
+	if rest == "" {
+		return true, "", nil
+	}
+
+	replacement := strings.TrimSpace(strings.TrimPrefix(rest, ":"))
+	if !strings.HasPrefix(rest, ":") || !strings.HasPrefix(replacement, "use ") {
+		return false, "", fmt.Errorf("a deprecation has to be formatted as '(deprecated)' or '(deprecated: use <code>)'")
+	}
+	replacement = strings.TrimSpace(replacement[len("use "):])
+	if err := checkErrorCodeValid(replacement); err != nil {
+		return false, "", fmt.Errorf("replacement of deprecated error code has invalid format: %v", err)
+	}
+	return true, replacement, nil
+}
+
 func (stateDone) step(sm *findErrorDocsSM, line string) error {
 	if strings.HasPrefix(line, "Errors:") {
 		return fmt.Errorf("repeated 'Errors:' block indicator")
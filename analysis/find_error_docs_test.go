@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseErrorDocs(t *testing.T) {
+	doc := `NewError creates an error.
+
+Errors:
+
+   - param: code        -- the code of the created error
+   - pkg-error-first    -- if the first thing happens
+   - pkg-error-second   --
+   - pkg-error-first    -- duplicate declarations are allowed
+`
+	docs, err := ParseErrorDocs(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &ErrorDocs{
+		Codes:            Set("pkg-error-first", "pkg-error-second"),
+		Descriptions:     map[string]string{"pkg-error-first": "if the first thing happens", "pkg-error-second": ""},
+		Param:            "code",
+		ParamDescription: "the code of the created error",
+	}
+	if !reflect.DeepEqual(expected, docs) {
+		t.Errorf("expected %+v but got %+v", expected, docs)
+	}
+
+	docs, err = ParseErrorDocs("Errors: none -- never fails.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !docs.NoCodes || len(docs.Codes) != 0 {
+		t.Errorf("expected no codes to be declared, but got %+v", docs)
+	}
+
+	if _, err := ParseErrorDocs("Errors:\n- pkg-error --"); err == nil {
+		t.Errorf("expected error for missing blank line")
+	}
+
+	docs, err = ParseErrorDocs("Errors:\n\n- ( internal ) pkg-error-retry --\n- pkg-error --\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedAttributes := map[string]CodeAttributes{"pkg-error-retry": {Internal: true}}
+	if !reflect.DeepEqual(expectedAttributes, docs.Attributes) || len(docs.Codes) != 2 {
+		t.Errorf("expected attributes %+v but got %+v", expectedAttributes, docs)
+	}
+
+	docs, err = ParseErrorDocs("Errors:\n\n- pkg-error-old -- (deprecated: use pkg-error-new) if it happens\n- pkg-error-older -- (deprecated)\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedAttributes = map[string]CodeAttributes{
+		"pkg-error-old":   {Deprecated: true, Replacement: "pkg-error-new"},
+		"pkg-error-older": {Deprecated: true},
+	}
+	if !reflect.DeepEqual(expectedAttributes, docs.Attributes) {
+		t.Errorf("expected attributes %+v but got %+v", expectedAttributes, docs.Attributes)
+	}
+
+	for _, doc := range []string{
+		"Errors:\n\n- (secret) pkg-error --",
+		"Errors:\n\n- (internal pkg-error --",
+		"Errors:\n\n- pkg-error -- (deprecated: pkg-error-new)",
+		"Errors:\n\n- pkg-error -- (deprecated: use pkg error new)",
+		"Errors:\n\n- pkg-error -- (deprecated",
+	} {
+		if _, err := ParseErrorDocs(doc); err == nil {
+			t.Errorf("expected error for invalid attribute in %q", doc)
+		}
+	}
+}
+
+func TestParseDeclarationLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		code   string
+		offset int
+	}{
+		{"- pkg-error -- if it happens", "pkg-error", 2},
+		{"-    pkg-error   --", "pkg-error", 5},
+		{"- (internal) pkg-error --", "pkg-error", 13},
+		{"- param: code --", "param: code", 2},
+	}
+
+	for _, test := range tests {
+		declaration, err := parseDeclarationLine(test.line)
+		if err != nil {
+			t.Errorf("parseDeclarationLine(%q) returned unexpected error: %v", test.line, err)
+			continue
+		}
+		if declaration.code != test.code || declaration.offset != test.offset || test.line[declaration.offset:][:len(test.code)] != test.code {
+			t.Errorf("parseDeclarationLine(%q) should return code %q at %d but returned %q at %d",
+				test.line, test.code, test.offset, declaration.code, declaration.offset)
+		}
+	}
+}
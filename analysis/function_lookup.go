@@ -17,6 +17,16 @@ type funcLookup struct {
 	methods    map[string][]*ast.FuncDecl // Mapping Method Names to Declarations (Multiple Possible per Name)
 	methodSet  typeutil.MethodSetCache
 	foundCodes map[funcDeclOrLit]CodeSet // Mapping Function Declarations and Function Literals to cached error codes
+
+	returnCodes map[*ast.ReturnStmt]CodeSet    // Mapping analysed return statements to the error codes they may return
+	codeSources map[funcDeclOrLit][]codeSource // Mapping analysed functions and function literals to the sources of their error codes
+}
+
+// codeSource is a called function, a created error type or a function literal, from which error codes flow into the errors returned by an analysed function.
+type codeSource struct {
+	object types.Object  // called function or created error type, nil for function literals
+	node   funcDeclOrLit // analysed definition of the called function or function literal, nil if it was not analysed
+	codes  CodeSet       // codes flowing from the source, nil if they are the codes found in node
 }
 
 func newFuncLookup() *funcLookup {
@@ -25,9 +35,16 @@ func newFuncLookup() *funcLookup {
 		map[string][]*ast.FuncDecl{},
 		typeutil.MethodSetCache{},
 		map[funcDeclOrLit]CodeSet{},
+		map[*ast.ReturnStmt]CodeSet{},
+		map[funcDeclOrLit][]codeSource{},
 	}
 }
 
+// addCodeSource records the given source of error codes returned by the given function.
+func (lookup *funcLookup) addCodeSource(function *funcDefinition, source codeSource) {
+	lookup.codeSources[function.node()] = append(lookup.codeSources[function.node()], source)
+}
+
 // collectFunctions creates a funcLookup using the given analysis object.
 func collectFunctions(pass *analysis.Pass) *funcLookup {
 	result := newFuncLookup()
@@ -0,0 +1,344 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// errorSources are the sources of the values of a local error variable.
+type errorSources struct {
+	codes   CodeSet       // union of the declared codes of all called functions
+	callees []*types.Func // the called functions, in order of appearance
+	unknown bool          // set, if any value does not come from a call to a function declaring error codes
+}
+
+// findUnreachableHandlers emits a diagnostic for each comparison of the error code of a local error variable with a constant,
+// where the constant is not declared by any of the functions the variable gets its value from.
+// Such handlers can never trigger, which usually happens when called functions change their error codes.
+//
+// Comparisons in the conditions of if statements, the cases of switch statements and lookups in map literals are checked.
+// The code of a variable "err" is retrieved by "err.Code()" or a function named "Code" taking the variable (e.g. "serum.Code(err)").
+func findUnreachableHandlers(c *context) {
+	sources := findErrorSources(c)
+	if len(sources) == 0 {
+		return
+	}
+	mapLiterals := findMapLiterals(c)
+
+	check := func(codeExpr ast.Expr, constExpr ast.Expr, rng ast.Node) {
+		variable := codeOfVariable(c, codeExpr)
+		source, ok := sources[variable]
+		if variable == nil || !ok || source.unknown {
+			return
+		}
+		value := c.pass.TypesInfo.Types[constExpr].Value
+		if value == nil || value.Kind() != constant.String {
+			return
+		}
+
+		code := constant.StringVal(value)
+		if checkErrorCodeValid(code) != nil {
+			return // e.g. the empty string, which is returned if there is no code
+		}
+		if _, ok := source.codes[code]; !ok {
+			reportUnreachableHandler(c, rng, code, source)
+		}
+	}
+
+	for _, file := range c.pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.BinaryExpr:
+				if node.Op == token.EQL || node.Op == token.NEQ {
+					check(node.X, node.Y, node)
+					check(node.Y, node.X, node)
+				}
+			case *ast.SwitchStmt:
+				if node.Tag == nil {
+					return true
+				}
+				for _, stmt := range node.Body.List {
+					for _, expr := range stmt.(*ast.CaseClause).List {
+						check(node.Tag, expr, expr)
+					}
+				}
+			case *ast.IndexExpr:
+				if compositeLit, ok := mapLiterals[astutil.Unparen(node.X)]; ok {
+					for _, element := range compositeLit.Elts {
+						if keyValue, ok := element.(*ast.KeyValueExpr); ok {
+							check(node.Index, keyValue.Key, node)
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// findErrorSources finds the sources of all local variables of the current package.
+// Variables with other sources than calls and nil are marked as unknown, as are parameters and variables whose address is taken.
+func findErrorSources(c *context) map[*types.Var]*errorSources {
+	pass := c.pass
+	result := map[*types.Var]*errorSources{}
+	get := func(ident *ast.Ident) *errorSources {
+		variable, ok := pass.TypesInfo.ObjectOf(ident).(*types.Var)
+		if !ok || variable.Parent() == nil || variable.Parent() == pass.Pkg.Scope() {
+			return nil
+		}
+		if result[variable] == nil {
+			result[variable] = &errorSources{codes: Set()}
+		}
+		return result[variable]
+	}
+	markUnknown := func(expr ast.Expr) {
+		if ident, ok := astutil.Unparen(expr).(*ast.Ident); ok {
+			if source := get(ident); source != nil {
+				source.unknown = true
+			}
+		}
+	}
+	add := func(lhs ast.Expr, rhs ast.Expr) {
+		ident, ok := astutil.Unparen(lhs).(*ast.Ident)
+		if !ok {
+			return
+		}
+		source := get(ident)
+		if source == nil {
+			return
+		}
+
+		if isNilLiteral(pass.TypesInfo, rhs) {
+			return
+		}
+		callExpr, ok := astutil.Unparen(rhs).(*ast.CallExpr)
+		if !ok {
+			source.unknown = true
+			return
+		}
+		callee, ok := typeutil.Callee(pass.TypesInfo, callExpr).(*types.Func)
+		var fact ErrorCodes
+		if !ok || !pass.ImportObjectFact(callee, &fact) {
+			source.unknown = true
+			return
+		}
+		source.codes = Union(source.codes, fact.Codes)
+		source.callees = append(source.callees, callee)
+	}
+	addAll := func(lhs []ast.Expr, rhs []ast.Expr) {
+		switch {
+		case len(rhs) == 1 && len(lhs) > 1:
+			if _, ok := astutil.Unparen(rhs[0]).(*ast.CallExpr); ok { // x, err := f()
+				for _, expr := range lhs[:len(lhs)-1] {
+					markUnknown(expr)
+				}
+				add(lhs[len(lhs)-1], rhs[0])
+				return
+			}
+			for _, expr := range lhs { // v, ok := m[k] and similar
+				markUnknown(expr)
+			}
+		case len(lhs) == len(rhs):
+			for i := range lhs {
+				add(lhs[i], rhs[i])
+			}
+		}
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.AssignStmt:
+				if node.Tok == token.ASSIGN || node.Tok == token.DEFINE {
+					addAll(node.Lhs, node.Rhs)
+				} else {
+					for _, expr := range node.Lhs {
+						markUnknown(expr)
+					}
+				}
+			case *ast.ValueSpec:
+				if len(node.Values) > 0 {
+					lhs := make([]ast.Expr, len(node.Names))
+					for i, name := range node.Names {
+						lhs[i] = name
+					}
+					addAll(lhs, node.Values)
+				}
+			case *ast.FuncType:
+				for _, fields := range []*ast.FieldList{node.Params, node.Results} {
+					if fields == nil {
+						continue
+					}
+					for _, field := range fields.List {
+						for _, name := range field.Names {
+							markUnknown(name)
+						}
+					}
+				}
+			case *ast.RangeStmt:
+				if node.Key != nil {
+					markUnknown(node.Key)
+				}
+				if node.Value != nil {
+					markUnknown(node.Value)
+				}
+			case *ast.UnaryExpr:
+				if node.Op == token.AND {
+					markUnknown(node.X)
+				}
+			}
+			return true
+		})
+	}
+
+	return result
+}
+
+// findMapLiterals finds the expressions referring to map literals with constant keys:
+// the literals themselves, and the identifiers of local variables initialized with a literal and never assigned otherwise.
+//
+// Package level maps are not included: they are usually shared tables (e.g. of HTTP status codes per error code),
+// which are looked up with the codes of many different calls.
+func findMapLiterals(c *context) map[ast.Expr]*ast.CompositeLit {
+	pass := c.pass
+	literals := map[types.Object]*ast.CompositeLit{}
+	assigned := map[types.Object]int{}
+	isMapLiteral := func(expr ast.Expr) (*ast.CompositeLit, bool) {
+		compositeLit, ok := astutil.Unparen(expr).(*ast.CompositeLit)
+		if !ok {
+			return nil, false
+		}
+		_, ok = pass.TypesInfo.TypeOf(compositeLit).Underlying().(*types.Map)
+		return compositeLit, ok
+	}
+	record := func(ident *ast.Ident, value ast.Expr) {
+		object := pass.TypesInfo.ObjectOf(ident)
+		if object == nil || object.Parent() == pass.Pkg.Scope() {
+			return
+		}
+		assigned[object]++
+		if compositeLit, ok := isMapLiteral(value); ok {
+			literals[object] = compositeLit
+		}
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.ValueSpec:
+				for i, name := range node.Names {
+					if len(node.Values) == len(node.Names) {
+						record(name, node.Values[i])
+					}
+				}
+			case *ast.AssignStmt:
+				for i, lhs := range node.Lhs {
+					if ident, ok := astutil.Unparen(lhs).(*ast.Ident); ok {
+						var value ast.Expr
+						if len(node.Lhs) == len(node.Rhs) {
+							value = node.Rhs[i]
+						}
+						record(ident, value)
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	result := map[ast.Expr]*ast.CompositeLit{}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.CompositeLit:
+				if _, ok := isMapLiteral(node); ok {
+					result[node] = node
+				}
+			case *ast.Ident:
+				object := pass.TypesInfo.Uses[node]
+				if compositeLit, ok := literals[object]; ok && assigned[object] == 1 {
+					result[node] = compositeLit
+				}
+			}
+			return true
+		})
+	}
+	return result
+}
+
+// codeOfVariable returns the variable, whose error code is retrieved by the given expression,
+// or nil if the expression does not retrieve the code of a variable.
+func codeOfVariable(c *context, expr ast.Expr) *types.Var {
+	ident, ok := astutil.Unparen(codeRetrievalTarget(expr)).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	variable, _ := c.pass.TypesInfo.Uses[ident].(*types.Var)
+	return variable
+}
+
+// codeRetrievalTarget returns the expression, whose error code is retrieved by the given expression,
+// or nil if the expression does not retrieve an error code.
+// The code is retrieved by "err.Code()" (also with a type assertion "err.(*Error).Code()"),
+// or a function named "Code" taking only the error (e.g. "serum.Code(err)").
+func codeRetrievalTarget(expr ast.Expr) ast.Expr {
+	callExpr, ok := astutil.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+
+	switch fun := astutil.Unparen(callExpr.Fun).(type) {
+	case *ast.SelectorExpr:
+		if fun.Sel.Name != "Code" {
+			return nil
+		}
+		if len(callExpr.Args) == 0 {
+			if typeAssert, ok := astutil.Unparen(fun.X).(*ast.TypeAssertExpr); ok {
+				return typeAssert.X
+			}
+			return fun.X
+		} else if len(callExpr.Args) == 1 {
+			return callExpr.Args[0]
+		}
+	case *ast.Ident:
+		if fun.Name == "Code" && len(callExpr.Args) == 1 {
+			return callExpr.Args[0]
+		}
+	}
+	return nil
+}
+
+func reportUnreachableHandler(c *context, rng ast.Node, code string, source *errorSources) {
+	callees := calleeNames(c.pass, source.callees)
+	verb := "returns"
+	if len(callees) > 1 {
+		verb = "return"
+	}
+
+	codes := source.codes.Slice()
+	sort.Strings(codes)
+	reportCodes(c.pass, categoryDeadHandler, rng, []string{code}, "handler for code %q can never trigger; %s %s %v",
+		code, strings.Join(callees, ", "), verb, codes)
+}
+
+// calleeNames returns the names of the given functions without duplicates, keeping the order of their first appearance.
+func calleeNames(pass *analysis.Pass, callees []*types.Func) []string {
+	seen := Set()
+	var result []string
+	for _, callee := range callees {
+		name := calleeName(pass, callee)
+		if _, ok := seen[name]; !ok {
+			seen.Add(name)
+			result = append(result, name)
+		}
+	}
+	return result
+}
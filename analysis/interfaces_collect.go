@@ -105,7 +105,7 @@ func checkIfErrorReturningInterface(pass *analysis.Pass, spec ast.Spec) *errorIn
 			// Figure out if method returns errors and try to get error code declarations.
 			errorMethod, err := checkIfInterfaceMethodDeclaresErrors(pass, interfaceType, element, elementType)
 			if err != nil {
-				pass.ReportRangef(element, "%v", err)
+				reportRange(pass, categoryInterface, element, "%v", err)
 			} else if errorMethod != nil {
 				result.errorMethods[errorMethod.ident.Name] = errorMethod
 			}
@@ -124,22 +124,24 @@ func checkIfInterfaceMethodDeclaresErrors(pass *analysis.Pass, interfaceType *as
 	}
 
 	methodIdent := method.Names[0]
-	codes, errorCodeParamName, declaredNoCodesOk, err := findErrorDocs(method.Doc)
+	docs, err := findErrorDocs(method.Doc)
 	if err != nil {
 		return nil, fmt.Errorf("interface method %q has odd docstring: %s", methodIdent.Name, err)
 	}
+	codes := docs.Codes
+	checkDeclaredCodesRegistered(pass, method.Doc, codes)
 
 	// TODO: Implement support, then remove this check
-	if errorCodeParamName != "" {
+	if docs.Param != "" {
 		return nil, fmt.Errorf("declaration of error constructors in interfaces is currently not supported")
 	}
 
-	errorCodeParam, ok := findErrorCodeParamIdent(pass, funcType, errorCodeParamName)
+	errorCodeParam, ok := findErrorCodeParamIdent(pass, funcType, docs.Param)
 	if !ok {
 		return nil, nil
 	}
 
-	if len(codes) == 0 && !declaredNoCodesOk && errorCodeParam == nil {
+	if len(codes) == 0 && !docs.NoCodes && errorCodeParam == nil {
 		// Exclude Cause() methods of error types from having to declare error codes.
 		interfaceType := pass.TypesInfo.TypeOf(interfaceType)
 		if methodIdent.Name == "Cause" && types.Implements(interfaceType, tReeErrorWithCause) {
@@ -149,7 +151,9 @@ func checkIfInterfaceMethodDeclaresErrors(pass *analysis.Pass, interfaceType *as
 		// Warn directly about any methods if they return errors, but don't declare error codes in their docs.
 		return nil, fmt.Errorf("interface method %q does not declare any error codes", methodIdent.Name)
 	} else {
-		return &errorMethod{methodIdent, funcCodes{codes, errorCodeParam}}, nil
+		checkInterfaceMethodCodeBudget(pass, methodIdent, codes)
+		checkInterfaceMethodBoundaries(pass, methodIdent, codes)
+		return &errorMethod{methodIdent, funcCodes{codes, errorCodeParam, docs.Attributes}}, nil
 	}
 }
 
@@ -225,7 +229,7 @@ func addEmbeddedInterfaceErrorMethodsForFact(pass *analysis.Pass, embedding *err
 	for methodName, newErrorMethodCodes := range add.ErrorMethods {
 		oldErrorMethod, ok := embedding.errorMethods[methodName]
 		if !ok {
-			embedding.errorMethods[methodName] = &errorMethod{nil, funcCodes{newErrorMethodCodes, nil}}
+			embedding.errorMethods[methodName] = &errorMethod{nil, funcCodes{newErrorMethodCodes, nil, nil}}
 			continue
 		}
 
@@ -237,7 +241,7 @@ func addEmbeddedInterfaceErrorMethodsForFact(pass *analysis.Pass, embedding *err
 func checkEmbeddedInterfaceErrorMethodCodes(pass *analysis.Pass, oldCodes CodeSet, newCodes CodeSet, methodName string, reportPos analysis.Range) {
 	errorCodesMatch, errorMessage := checkIfErrorCodesMatch(oldCodes, newCodes)
 	if !errorCodesMatch {
-		pass.ReportRangef(reportPos, "embedded interface is not compatible: method %q has mismatches in declared error codes: %s", methodName, errorMessage)
+		reportRange(pass, categoryInterface, reportPos, "embedded interface is not compatible: method %q has mismatches in declared error codes: %s", methodName, errorMessage)
 	}
 }
 
@@ -254,7 +258,7 @@ func exportInterfaceFacts(pass *analysis.Pass, interfaces []*errorInterfaceInter
 			if errorMethod.codes.param != nil {
 				exportErrorConstructorFact(pass, errorMethod.ident, errorMethod.codes.param)
 			}
-			exportErrorCodesFact(pass, errorMethod.ident, errorMethod.codes.codes)
+			exportErrorCodesFact(pass, errorMethod.ident, &ErrorCodes{Codes: errorMethod.codes.codes, Attributes: errorMethod.codes.attributes})
 		}
 	}
 }
@@ -521,7 +521,7 @@ func checkIfTypeIsValidSubtypeForInterface(c *context, errorInterface *ErrorInte
 			namedType := getNamedType(interfaceType)
 			unexpectedCodes := unexpectedCodes.Slice()
 			sort.Strings(unexpectedCodes)
-			pass.ReportRangef(exprPos, "cannot use expression as %q value: method %q declares the following error codes which were not part of the interface: %v", namedType.Obj().Name(), methodName, unexpectedCodes)
+			reportCodes(pass, categoryInterface, exprPos, unexpectedCodes, "cannot use expression as %q value: method %q declares the following error codes which were not part of the interface: %v", namedType.Obj().Name(), methodName, unexpectedCodes)
 		}
 	}
 }
@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkFunctionInternalCodes emits a diagnostic for each internal error code declared by the given exported function,
+// if the current package is public. The diagnostic lists the called functions introducing the code.
+func checkFunctionInternalCodes(c *context, funcDecl *ast.FuncDecl, codes CodeSet) {
+	if !isExportedFunc(c.pass, funcDecl) || !isPublicPackage(c.pass) {
+		return
+	}
+
+	for _, code := range findInternalCodes(c, codes) {
+		reportInternalCode(c.pass, funcDecl.Name, fmt.Sprintf("function %q", funcDecl.Name.Name), code,
+			"; introduced by: "+describeCodeSources(c, funcDecl, code))
+	}
+}
+
+// checkInterfaceInternalCodes emits a diagnostic for each internal error code declared by an exported method of the given interfaces,
+// if the current package is public.
+func checkInterfaceInternalCodes(c *context, interfaces []*errorInterfaceInternal) {
+	if !isPublicPackage(c.pass) {
+		return
+	}
+
+	for _, errorInterface := range interfaces {
+		for _, errorMethod := range errorInterface.errorMethods {
+			if errorMethod.ident == nil || !errorMethod.ident.IsExported() {
+				continue // methods of embedded interfaces are checked in their own package
+			}
+
+			for _, code := range findInternalCodes(c, errorMethod.codes.codes) {
+				reportInternalCode(c.pass, errorMethod.ident, fmt.Sprintf("interface method %q", errorMethod.ident.Name), code, "")
+			}
+		}
+	}
+}
+
+// findInternalCodes returns the sorted internal codes of the given codes.
+func findInternalCodes(c *context, codes CodeSet) []string {
+	var result []string
+	for code := range codes {
+		if c.attributes[code].Internal {
+			result = append(result, code)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// isPublicPackage reports whether the package of the given pass is part of the public API of its module,
+// which is the case if it is not a main package and its import path does not contain an "internal" element.
+func isPublicPackage(pass *analysis.Pass) bool {
+	if pass.Pkg.Name() == "main" {
+		return false
+	}
+	for _, element := range strings.Split(pass.Pkg.Path(), "/") {
+		if element == "internal" {
+			return false
+		}
+	}
+	return true
+}
+
+func reportInternalCode(pass *analysis.Pass, rng analysis.Range, subject, code, details string) {
+	reportCodes(pass, categoryInternalCode, rng, []string{code},
+		"%s declares internal error code %q, which must not escape the public package %q%s",
+		subject, code, pass.Pkg.Path(), details)
+}
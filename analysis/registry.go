@@ -0,0 +1,185 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"gopkg.in/yaml.v3"
+)
+
+// Stability levels of registered error codes.
+const (
+	StabilityStable       = "stable"
+	StabilityExperimental = "experimental"
+	StabilityDeprecated   = "deprecated"
+)
+
+// Registry is the central list of all valid error codes of a project.
+//
+// The registry is a ".json", ".yaml" or ".yml" file, referenced by the "registry" field of the configuration file.
+// If a registry is configured, every error code used in the analysed packages has to be registered.
+type Registry struct {
+	Codes []RegisteredCode `json:"codes" yaml:"codes"`
+}
+
+// RegisteredCode is a single entry of the registry.
+type RegisteredCode struct {
+	Code        string `json:"code" yaml:"code"`
+	Owner       string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Stability is one of "stable", "experimental" or "deprecated". If empty, "stable" is assumed.
+	Stability string `json:"stability,omitempty" yaml:"stability,omitempty"`
+
+	// Replacement is the registered code to use instead of a deprecated code, if any.
+	Replacement string `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+}
+
+// ReadRegistry reads and validates the registry file at the given path.
+// The file is decoded as YAML if it has a ".yaml" or ".yml" extension, otherwise as JSON.
+func ReadRegistry(path string) (*Registry, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read registry: %v", err)
+	}
+
+	registry := &Registry{}
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(registry)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(registry)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry %q: %v", path, err)
+	}
+
+	if err := registry.validate(); err != nil {
+		return nil, fmt.Errorf("invalid registry %q: %v", path, err)
+	}
+	return registry, nil
+}
+
+// FindRegistryFile returns the path of the registry configured for packages in the given directory,
+// or the empty string if there is none.
+// The configuration file is searched like the analyzer does.
+func FindRegistryFile(dir string) (string, error) {
+	configFile := findConfigFile(dir)
+	if configFile == "" {
+		return "", nil
+	}
+
+	config, err := readConfig(configFile)
+	if err != nil {
+		return "", err
+	}
+	return config.registryPath(configFile), nil
+}
+
+func (registry *Registry) validate() error {
+	seen := Set()
+	for _, entry := range registry.Codes {
+		if err := checkErrorCodeValid(entry.Code); err != nil {
+			return fmt.Errorf("registered error code %q is invalid: %v", entry.Code, err)
+		}
+		if _, ok := seen[entry.Code]; ok {
+			return fmt.Errorf("error code %q is registered more than once", entry.Code)
+		}
+		seen.Add(entry.Code)
+
+		switch entry.Stability {
+		case "", StabilityStable, StabilityExperimental, StabilityDeprecated:
+		default:
+			return fmt.Errorf("stability of error code %q has to be %q, %q or %q, but was %q",
+				entry.Code, StabilityStable, StabilityExperimental, StabilityDeprecated, entry.Stability)
+		}
+		if entry.Replacement != "" && entry.Stability != StabilityDeprecated {
+			return fmt.Errorf("error code %q has a replacement, but is not deprecated", entry.Code)
+		}
+	}
+
+	for _, entry := range registry.Codes {
+		if _, ok := seen[entry.Replacement]; entry.Replacement != "" && !ok {
+			return fmt.Errorf("replacement %q of error code %q is not registered", entry.Replacement, entry.Code)
+		}
+	}
+	return nil
+}
+
+// Lookup returns the registry entry of the given code.
+func (registry *Registry) Lookup(code string) (RegisteredCode, bool) {
+	for _, entry := range registry.Codes {
+		if entry.Code == code {
+			return entry, true
+		}
+	}
+	return RegisteredCode{}, false
+}
+
+// codeSet returns the set of all registered codes.
+func (registry *Registry) codeSet() CodeSet {
+	result := Set()
+	for _, entry := range registry.Codes {
+		result.Add(entry.Code)
+	}
+	return result
+}
+
+// attributes returns the attributes of the deprecated codes of the registry.
+func (registry *Registry) attributes() map[string]CodeAttributes {
+	result := map[string]CodeAttributes{}
+	for _, entry := range registry.Codes {
+		if entry.Stability == StabilityDeprecated {
+			result[entry.Code] = CodeAttributes{Deprecated: true, Replacement: entry.Replacement}
+		}
+	}
+	return result
+}
+
+// checkErrorCodeRegistered emits a diagnostic if a registry is configured and the given code is not part of it.
+func checkErrorCodeRegistered(pass *analysis.Pass, rng analysis.Range, code string) {
+	registered := getPackageConfig(pass).registeredCodes
+	if registered == nil {
+		return
+	}
+
+	if _, ok := registered[code]; ok {
+		return
+	}
+
+	if suggestion, ok := nearestCode(code, registered); ok {
+		reportCodesWithFixes(pass, categoryUnregisteredCode, rng, []string{code}, codeReplacementFix(rng, code, suggestion),
+			"error code %q is not registered, did you mean %q?", code, suggestion)
+		return
+	}
+	reportCodes(pass, categoryUnregisteredCode, rng, []string{code}, "error code %q is not registered", code)
+}
+
+// checkDeclaredCodesRegistered emits a diagnostic for each code declared in the given docstring,
+// that is not part of the configured registry.
+// The diagnostic is reported at the line declaring the code, if it can be found.
+func checkDeclaredCodesRegistered(pass *analysis.Pass, doc *ast.CommentGroup, codes CodeSet) {
+	if getPackageConfig(pass).registeredCodes == nil || doc == nil {
+		return
+	}
+
+	sortedCodes := codes.Slice()
+	sort.Strings(sortedCodes)
+	for _, code := range sortedCodes {
+		var rng analysis.Range = doc
+		if comment, _ := docCommentForCode(doc, code); comment != nil {
+			rng = comment
+		}
+		checkErrorCodeRegistered(pass, rng, code)
+	}
+}
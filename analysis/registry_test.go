@@ -0,0 +1,54 @@
+package analysis
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadRegistry(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		content  string
+		expected string // expected error, or empty
+	}{
+		{"valid.yaml", "codes:\n  - code: pkg-error\n    owner: me\n    stability: experimental\n", ""},
+		{"valid.json", `{"codes": [{"code": "pkg-error", "description": "some error"}]}`, ""},
+		{"unknown-field.json", `{"codes": [{"code": "pkg-error", "team": "me"}]}`, "unknown field"},
+		{"invalid-code.yaml", "codes:\n  - code: pkg error\n", "is invalid"},
+		{"duplicate.yaml", "codes:\n  - code: pkg-error\n  - code: pkg-error\n", "registered more than once"},
+		{"stability.yaml", "codes:\n  - code: pkg-error\n    stability: unstable\n", "stability of error code"},
+		{"replacement.yaml", "codes:\n  - code: pkg-old\n    stability: deprecated\n    replacement: pkg-new\n  - code: pkg-new\n", ""},
+		{"replacement.yaml", "codes:\n  - code: pkg-old\n    replacement: pkg-new\n  - code: pkg-new\n", "has a replacement, but is not deprecated"},
+		{"replacement.yaml", "codes:\n  - code: pkg-old\n    stability: deprecated\n    replacement: pkg-new\n", `replacement "pkg-new" of error code "pkg-old" is not registered`},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(dir, test.name)
+		if err := ioutil.WriteFile(path, []byte(test.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := ReadRegistry(path)
+		switch {
+		case test.expected == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		case test.expected != "" && err == nil:
+			t.Errorf("%s: expected error containing %q", test.name, test.expected)
+		case test.expected != "" && !strings.Contains(err.Error(), test.expected):
+			t.Errorf("%s: expected error containing %q, but got: %v", test.name, test.expected, err)
+		}
+	}
+}
+
+func TestFindRegistryFile(t *testing.T) {
+	path, err := FindRegistryFile(filepath.Join("testdata", "src", "registry"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := filepath.Join("testdata", "src", "registry", "codes.yaml"); path != expected {
+		t.Errorf("expected registry %q, but got %q", expected, path)
+	}
+}
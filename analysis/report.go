@@ -0,0 +1,114 @@
+package analysis
+
+import (
+	"fmt"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Categories of the diagnostics emitted by the analyzer.
+//
+// The names are stable: they are used to refer to diagnostics from configuration files,
+// so they must not be changed once released.
+const (
+	categoryErrorPosition    = "error-position"
+	categoryDocFormat        = "doc-format"
+	categoryMissingDoc       = "missing-doc"
+	categoryCodeMismatch     = "code-mismatch"
+	categoryUnsupported      = "unsupported"
+	categoryUndeclaredCallee = "undeclared-callee"
+	categoryErrorSource      = "error-source"
+	categoryInvalidCode      = "invalid-code"
+	categoryCodeNaming       = "code-naming"
+	categoryAnnotation       = "annotation"
+	categoryErrorType        = "error-type"
+	categoryErrorConstructor = "error-constructor"
+	categoryInterface        = "interface"
+	categoryUnregisteredCode = "unregistered-code"
+	categoryCodeBudget       = "code-budget"
+	categoryCodeLeak         = "code-leak"
+	categoryInternalCode     = "internal-code"
+	categoryDeprecatedCode   = "deprecated-code"
+	categoryDeprecatedCheck  = "deprecated-check"
+	categoryDiscardedError   = "discarded-error"
+	categoryDeadHandler      = "dead-handler"
+	categoryTypedNil         = "typed-nil"
+	categoryStaleError       = "stale-error"
+	categoryTestExpectation  = "test-expectation"
+)
+
+// categories maps the name of each diagnostic category to a short description of it.
+var categories = map[string]string{
+	categoryErrorPosition:    "an error is returned, but not as the last result",
+	categoryDocFormat:        "the error code declaration in a docstring is malformed",
+	categoryMissingDoc:       "an error returning function does not declare its error codes",
+	categoryCodeMismatch:     "the declared error codes do not match the actual error codes",
+	categoryUnsupported:      "the code uses a construct that is not supported by the error code analysis",
+	categoryUndeclaredCallee: "a called function does not declare its error codes",
+	categoryErrorSource:      "an error originates from a source that cannot be tracked",
+	categoryInvalidCode:      "an error code is not a valid constant error code",
+	categoryCodeNaming:       "an error code does not follow the naming rules of its package",
+	categoryAnnotation:       "a return statement annotation is malformed",
+	categoryErrorType:        "an error type does not have a legible Code() method",
+	categoryErrorConstructor: "an error constructor or its error code parameter is used incorrectly",
+	categoryInterface:        "error codes of an interface method and its implementation are incompatible",
+	categoryUnregisteredCode: "an error code is not declared in the error code registry",
+	categoryCodeBudget:       "an exported function declares more error codes than allowed",
+	categoryCodeLeak:         "an exported function declares an error code, which must not cross the boundary of its package",
+	categoryInternalCode:     "an exported function of a public package declares an internal error code",
+	categoryDeprecatedCode:   "a function starts to return a deprecated error code",
+	categoryDeprecatedCheck:  "a deprecated error code is checked in an if or switch statement",
+	categoryDiscardedError:   "the error result of a function declaring error codes is discarded",
+	categoryDeadHandler:      "an error code is checked, which the called functions never return",
+	categoryTypedNil:         "a nil pointer to an error type is returned as a non-nil error",
+	categoryStaleError:       "a returned error variable was not assigned by the failing call",
+	categoryTestExpectation:  "a test expects an error code, which the function under test does not declare",
+}
+
+// Categories returns the names of all diagnostic categories, mapped to a short description of each category.
+//
+// The names are stable, so they can be used as rule identifiers by tools processing the diagnostics.
+func Categories() map[string]string {
+	result := make(map[string]string, len(categories))
+	for name, description := range categories {
+		result[name] = description
+	}
+	return result
+}
+
+// report emits a diagnostic of the given category at the given position.
+func report(pass *analysis.Pass, category string, pos token.Pos, format string, args ...interface{}) {
+	emit(pass, analysis.Diagnostic{Pos: pos, Category: category, Message: fmt.Sprintf(format, args...)}, nil)
+}
+
+// reportRange emits a diagnostic of the given category for the given range.
+func reportRange(pass *analysis.Pass, category string, rng analysis.Range, format string, args ...interface{}) {
+	emit(pass, analysis.Diagnostic{Pos: rng.Pos(), End: rng.End(), Category: category, Message: fmt.Sprintf(format, args...)}, nil)
+}
+
+// reportCodes emits a diagnostic of the given category for the given range,
+// which is about the given error codes.
+//
+// The codes identify the diagnostic in a baseline, so they should be the codes causing the problem.
+func reportCodes(pass *analysis.Pass, category string, rng analysis.Range, codes []string, format string, args ...interface{}) {
+	emit(pass, analysis.Diagnostic{Pos: rng.Pos(), End: rng.End(), Category: category, Message: fmt.Sprintf(format, args...)}, codes)
+}
+
+// reportCodesWithFixes emits a diagnostic like reportCodes, with the given suggested fixes.
+func reportCodesWithFixes(pass *analysis.Pass, category string, rng analysis.Range, codes []string, fixes []analysis.SuggestedFix, format string, args ...interface{}) {
+	emit(pass, analysis.Diagnostic{Pos: rng.Pos(), End: rng.End(), Category: category, Message: fmt.Sprintf(format, args...), SuggestedFixes: fixes}, codes)
+}
+
+// emit reports the given diagnostic,
+// unless the configuration of the current package suppresses it or it is part of the baseline.
+func emit(pass *analysis.Pass, diagnostic analysis.Diagnostic, codes []string) {
+	config := getPackageConfig(pass)
+	if config.suppresses(pass, diagnostic) {
+		return
+	}
+	if config.baseline != nil && config.baseline.suppresses(pass, diagnostic, codes) {
+		return
+	}
+	pass.Report(diagnostic)
+}
@@ -0,0 +1,204 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Result is the result of the Analyzer for a single package.
+//
+// Other analyzers can require the Analyzer and use the result to look up error codes,
+// instead of parsing the error code docs themselves:
+//
+//	var MyAnalyzer = &analysis.Analyzer{
+//		Requires: []*analysis.Analyzer{serum.Analyzer},
+//		...
+//	}
+//
+//	result := pass.ResultOf[serum.Analyzer].(*serum.Result)
+//
+// All returned code sets are copies and may be modified by the caller.
+type Result struct {
+	funcCodes    map[*types.Func]CodeSet
+	errorTypes   map[*types.TypeName]*ErrorType
+	returnCodes  map[*ast.ReturnStmt]CodeSet
+	docs         []FunctionDoc
+	expectations []CodeExpectation
+
+	funcDecls   map[*types.Func]*ast.FuncDecl
+	foundCodes  map[funcDeclOrLit]CodeSet
+	codeSources map[funcDeclOrLit][]codeSource
+}
+
+// CodeSource is a called function or a created error type, from which error codes flow into the errors returned by a function.
+type CodeSource struct {
+	// Object is the called function or method, or the type name of the created error type.
+	Object types.Object
+	// Codes are the error codes flowing from the source into the returned errors.
+	Codes CodeSet
+}
+
+// newResult creates the result of the given pass from all facts known to the pass,
+// and the codes found in the return statements of the analysed functions.
+func newResult(pass *analysis.Pass, lookup *funcLookup) *Result {
+	result := &Result{
+		funcCodes:   map[*types.Func]CodeSet{},
+		errorTypes:  map[*types.TypeName]*ErrorType{},
+		returnCodes: lookup.returnCodes,
+		funcDecls:   map[*types.Func]*ast.FuncDecl{},
+		foundCodes:  lookup.foundCodes,
+		codeSources: lookup.codeSources,
+	}
+
+	for node := range lookup.foundCodes {
+		if funcDecl, ok := node.(*ast.FuncDecl); ok {
+			if fn, ok := pass.TypesInfo.Defs[funcDecl.Name].(*types.Func); ok {
+				result.funcDecls[fn] = funcDecl
+			}
+		}
+	}
+
+	for _, objectFact := range pass.AllObjectFacts() {
+		switch fact := objectFact.Fact.(type) {
+		case *ErrorCodes:
+			if fn, ok := objectFact.Object.(*types.Func); ok {
+				result.funcCodes[fn] = fact.Codes
+			}
+		case *ErrorType:
+			if typeName, ok := objectFact.Object.(*types.TypeName); ok {
+				result.errorTypes[typeName] = fact
+			}
+		}
+	}
+
+	return result
+}
+
+// CodesOf returns the error codes declared by the given function, method or interface method.
+// The function may belong to the current package or to one of its dependencies.
+//
+// The second result is false, if the function does not declare any error codes.
+func (r *Result) CodesOf(fn *types.Func) (CodeSet, bool) {
+	codes, ok := r.funcCodes[fn]
+	if !ok {
+		return nil, false
+	}
+	return Union(codes, nil), true
+}
+
+// ErrorTypeOf returns the error type information for the given type, or a pointer to it.
+// The type may belong to the current package or to one of its dependencies.
+//
+// The second result is false, if the type is not an error type with a Code() method.
+func (r *Result) ErrorTypeOf(typ types.Type) (*ErrorType, bool) {
+	named := getNamedType(typ)
+	if named == nil {
+		return nil, false
+	}
+
+	errorType, ok := r.errorTypes[named.Obj()]
+	if !ok {
+		return nil, false
+	}
+
+	result := &ErrorType{Field: errorType.Field}
+	result.Codes = append(result.Codes, errorType.Codes...)
+	return result, true
+}
+
+// ReturnCodes returns the error codes that may be returned by the given return statement,
+// after applying the error code annotations of the statement.
+//
+// Return statements are only analysed in functions of the current package, that declare error codes,
+// and in the functions and function literals they use.
+// For all other return statements the second result is false.
+func (r *Result) ReturnCodes(stmt *ast.ReturnStmt) (CodeSet, bool) {
+	codes, ok := r.returnCodes[stmt]
+	if !ok {
+		return nil, false
+	}
+	return Union(codes, nil), true
+}
+
+// DocCoverage returns the documentation state of all error returning functions, methods and interface methods
+// declared in the current package.
+func (r *Result) DocCoverage() []FunctionDoc {
+	return append([]FunctionDoc(nil), r.docs...)
+}
+
+// Expectations returns the error codes expected by calls of serumtest.ExpectCode in the current package,
+// for which the functions under test are known.
+func (r *Result) Expectations() []CodeExpectation {
+	return append([]CodeExpectation(nil), r.expectations...)
+}
+
+// CodeSources returns the sources of the error codes returned by the given function of the current package, sorted by package and name.
+//
+// The sources are taken from the analysis of the return statements, so calls whose errors are not returned are not included.
+// Called functions of the current package without declared error codes and function literals are not sources themselves:
+// the sources of their returned errors are included instead.
+//
+// The second result is false, if the function was not analysed.
+func (r *Result) CodeSources(fn *types.Func) ([]CodeSource, bool) {
+	funcDecl, ok := r.funcDecls[fn]
+	if !ok {
+		return nil, false
+	}
+
+	found := map[types.Object]CodeSet{}
+	r.collectCodeSources(funcDecl, r.foundCodes[funcDecl], map[funcDeclOrLit]struct{}{}, found)
+
+	result := make([]CodeSource, 0, len(found))
+	for object, codes := range found {
+		result = append(result, CodeSource{object, codes})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Object, result[j].Object
+		if a.Pkg().Path() != b.Pkg().Path() {
+			return a.Pkg().Path() < b.Pkg().Path()
+		}
+		return a.Name() < b.Name()
+	})
+	return result, true
+}
+
+// collectCodeSources adds the sources of the given codes returned by the given function or function literal to found.
+func (r *Result) collectCodeSources(node funcDeclOrLit, codes CodeSet, visited map[funcDeclOrLit]struct{}, found map[types.Object]CodeSet) {
+	if _, ok := visited[node]; ok {
+		return
+	}
+	visited[node] = struct{}{}
+
+	for _, source := range r.codeSources[node] {
+		sourceCodes := source.codes
+		if sourceCodes == nil {
+			sourceCodes = r.foundCodes[source.node]
+		}
+		sourceCodes = Intersection(sourceCodes, codes)
+		if len(sourceCodes) == 0 {
+			continue
+		}
+
+		if r.isCodeSource(source.object) {
+			found[source.object] = Union(found[source.object], sourceCodes)
+		} else if source.node != nil {
+			r.collectCodeSources(source.node, sourceCodes, visited, found)
+		}
+	}
+}
+
+// isCodeSource checks if the given object is a function declaring error codes, or an error type.
+func (r *Result) isCodeSource(object types.Object) bool {
+	switch object := object.(type) {
+	case *types.Func:
+		_, ok := r.funcCodes[object]
+		return ok && object.Pkg() != nil
+	case *types.TypeName:
+		return object.Pkg() != nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,90 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// resultAnalyzer reports the information of the result of Analyzer for all calls, return statements and composite literals,
+// and the documentation state of all error returning functions.
+var resultAnalyzer = &analysis.Analyzer{
+	Name:     "result",
+	Doc:      "test analyzer using the result of the serum analyzer",
+	Requires: []*analysis.Analyzer{Analyzer},
+	Run:      runResult,
+}
+
+func runResult(pass *analysis.Pass) (interface{}, error) {
+	result := pass.ResultOf[Analyzer].(*Result)
+
+	for _, doc := range result.DocCoverage() {
+		pass.Reportf(doc.Func.Pos(), "doc: %s", doc.State)
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FuncDecl:
+				if fn, ok := pass.TypesInfo.Defs[node.Name].(*types.Func); ok {
+					if sources, ok := result.CodeSources(fn); ok && len(sources) > 0 {
+						reportSources(pass, node.Name, sources)
+					}
+				}
+			case *ast.CallExpr:
+				if fn, ok := typeutil.Callee(pass.TypesInfo, node).(*types.Func); ok {
+					if codes, ok := result.CodesOf(fn); ok {
+						pass.Reportf(node.Pos(), "call %s.%s: %s", fn.Pkg().Name(), fn.Name(), sortedCodes(codes))
+					}
+				}
+				if errorType, ok := result.ErrorTypeOf(pass.TypesInfo.TypeOf(node)); ok {
+					reportErrorType(pass, node, errorType)
+				}
+			case *ast.CompositeLit:
+				if errorType, ok := result.ErrorTypeOf(pass.TypesInfo.TypeOf(node)); ok {
+					reportErrorType(pass, node, errorType)
+				}
+			case *ast.ReturnStmt:
+				if codes, ok := result.ReturnCodes(node); ok && len(codes) > 0 {
+					pass.Reportf(node.Pos(), "return: %s", sortedCodes(codes))
+				}
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+func reportErrorType(pass *analysis.Pass, node ast.Node, errorType *ErrorType) {
+	named := getNamedType(pass.TypesInfo.TypeOf(node.(ast.Expr)))
+	name := named.Obj().Pkg().Name() + "." + named.Obj().Name()
+	if errorType.Field != nil {
+		pass.Reportf(node.Pos(), "error type %s: field %s", name, errorType.Field.Name)
+	} else {
+		pass.Reportf(node.Pos(), "error type %s: %s", name, strings.Join(errorType.Codes, " "))
+	}
+}
+
+func reportSources(pass *analysis.Pass, node ast.Node, sources []CodeSource) {
+	var descriptions []string
+	for _, source := range sources {
+		descriptions = append(descriptions, source.Object.Pkg().Name()+"."+source.Object.Name()+": "+sortedCodes(source.Codes))
+	}
+	pass.Reportf(node.Pos(), "sources: %s", strings.Join(descriptions, "; "))
+}
+
+func sortedCodes(codes CodeSet) string {
+	slice := codes.Slice()
+	sort.Strings(slice)
+	return strings.Join(slice, " ")
+}
+
+func TestResult(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), resultAnalyzer, "result")
+}
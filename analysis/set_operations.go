@@ -70,3 +70,15 @@ func Difference(set, subtrahend CodeSet) CodeSet {
 	}
 	return diff
 }
+
+// Intersection creates a new set containing the elements that appear in both input sets.
+// The input sets are not modified.
+func Intersection(set, other CodeSet) CodeSet {
+	intersection := make(CodeSet)
+	for value := range set {
+		if _, ok := other[value]; ok {
+			intersection[value] = struct{}{}
+		}
+	}
+	return intersection
+}
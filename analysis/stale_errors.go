@@ -0,0 +1,147 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// findStaleErrorReturns emits a diagnostic for each return of a stale error variable:
+// an error variable returned in the body of an if statement, whose condition checks another error variable for nil,
+// while the returned variable was assigned by an earlier call than the checked one.
+//
+// For example "x, err := a(); if y, err2 := b(); err2 != nil { return err }" returns the error of a,
+// even though b failed. The codes of a may be declared, so the mismatch would not be found otherwise.
+//
+// The values of both variables are found using their direct taint spread.
+// Returns are not reported, if the returned variable is assigned within the body of the if statement before the return.
+func findStaleErrorReturns(c *context) {
+	pass := c.pass
+	c.lookup.forEach(func(funcDecl *ast.FuncDecl) {
+		if funcDecl.Body == nil || !returnsError(pass, funcDecl.Type) {
+			return
+		}
+
+		function := &funcDefinition{funcDecl, nil}
+		reported := map[*ast.ReturnStmt]struct{}{}
+		ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.IfStmt:
+				checked := findErrorsCheckedNotNil(c, node.Cond)
+				if len(checked) == 0 {
+					return true
+				}
+
+				forEachReturnStmt(node.Body, func(returnStmt *ast.ReturnStmt) {
+					if _, ok := reported[returnStmt]; ok || len(returnStmt.Results) == 0 {
+						return
+					}
+					if checkStaleErrorReturn(c, function, node, checked, returnStmt) {
+						reported[returnStmt] = struct{}{}
+					}
+				})
+			}
+			return true
+		})
+	})
+}
+
+// findErrorsCheckedNotNil finds the identifiers of error variables compared with "!= nil" in the given condition,
+// including the operands of && and || operators.
+func findErrorsCheckedNotNil(c *context, cond ast.Expr) []*ast.Ident {
+	var result []*ast.Ident
+	binary, ok := astutil.Unparen(cond).(*ast.BinaryExpr)
+	if !ok {
+		return nil
+	}
+
+	switch binary.Op {
+	case token.LAND, token.LOR:
+		result = append(result, findErrorsCheckedNotNil(c, binary.X)...)
+		result = append(result, findErrorsCheckedNotNil(c, binary.Y)...)
+	case token.NEQ:
+		info := c.pass.TypesInfo
+		for _, operands := range [][2]ast.Expr{{binary.X, binary.Y}, {binary.Y, binary.X}} {
+			ident, ok := astutil.Unparen(operands[0]).(*ast.Ident)
+			if ok && ident.Obj != nil && isNilLiteral(info, operands[1]) && types.Implements(info.TypeOf(ident), tError) {
+				result = append(result, ident)
+			}
+		}
+	}
+	return result
+}
+
+// checkStaleErrorReturn emits a diagnostic if the given return statement returns a stale error variable.
+// It returns true, if a diagnostic was emitted.
+func checkStaleErrorReturn(c *context, function *funcDefinition, ifStmt *ast.IfStmt, checked []*ast.Ident, returnStmt *ast.ReturnStmt) bool {
+	pass := c.pass
+	returned, ok := astutil.Unparen(returnStmt.Results[len(returnStmt.Results)-1]).(*ast.Ident)
+	if !ok || returned.Obj == nil || returned.Obj.Kind != ast.Var || !types.Implements(pass.TypesInfo.TypeOf(returned), tError) {
+		return false
+	}
+	for _, ident := range checked {
+		if ident.Obj == returned.Obj {
+			return false
+		}
+	}
+
+	returnedSources := findAssignedValues(pass, function, returned)
+	for _, source := range returnedSources {
+		if source.Pos() >= ifStmt.Body.Pos() && source.Pos() < returnStmt.Pos() {
+			return false // the returned variable was updated in the body
+		}
+	}
+	returnedCall := findLatestCall(returnedSources, ifStmt.Pos())
+	if returnedCall == nil {
+		return false
+	}
+
+	for _, ident := range checked {
+		checkedCall := findLatestCall(findAssignedValues(pass, function, ident), ifStmt.Body.Pos())
+		if checkedCall == nil || checkedCall.Pos() < returnedCall.Pos() {
+			continue
+		}
+
+		reportRange(pass, categoryStaleError, returned, "returned error %q was assigned by the earlier call to %s, but the checked error %q comes from %s",
+			returned.Name, describeCall(c, returnedCall), ident.Name, describeCall(c, checkedCall))
+		return true
+	}
+	return false
+}
+
+// findAssignedValues finds all values directly assigned to the given variable within the given function.
+// For destructuring assignments the assigned call is returned.
+func findAssignedValues(pass *analysis.Pass, function *funcDefinition, ident *ast.Ident) []ast.Expr {
+	taintResult := taintSpreadForIdentDirect(pass, ident, function)
+	result := append([]ast.Expr{}, taintResult.expressions...)
+	for _, destruct := range taintResult.destructAssignment {
+		result = append(result, destruct.source)
+	}
+	return result
+}
+
+// findLatestCall returns the call with the greatest position before the given position, or nil if there is none.
+func findLatestCall(exprs []ast.Expr, before token.Pos) *ast.CallExpr {
+	var result *ast.CallExpr
+	for _, expr := range exprs {
+		callExpr, ok := astutil.Unparen(expr).(*ast.CallExpr)
+		if ok && callExpr.Pos() < before && (result == nil || callExpr.Pos() > result.Pos()) {
+			result = callExpr
+		}
+	}
+	return result
+}
+
+// describeCall returns the name of the function called by the given call, or "a function" if it is unknown.
+func describeCall(c *context, callExpr *ast.CallExpr) string {
+	if callee, ok := typeutil.Callee(c.pass.TypesInfo, callExpr).(*types.Func); ok {
+		return calleeName(c.pass, callee)
+	}
+	return "a function"
+}
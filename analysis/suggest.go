@@ -0,0 +1,239 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// maxTypoDistance is the maximum edit distance between two codes, for one to be considered a misspelling of the other.
+const maxTypoDistance = 2
+
+// editDistance computes the optimal string alignment distance between the given strings:
+// the number of insertions, deletions, substitutions and transpositions of adjacent characters
+// needed to turn one into the other.
+func editDistance(a, b string) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			rows[i][j] = min3(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && rows[i-2][j-2]+1 < rows[i][j] {
+				rows[i][j] = rows[i-2][j-2] + 1
+			}
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// nearestCode finds the candidate that is most likely the intended spelling of the given code.
+// Candidates with the same distance are ordered alphabetically.
+// The second result is false, if no candidate is close enough.
+func nearestCode(code string, candidates CodeSet) (string, bool) {
+	sortedCandidates := candidates.Slice()
+	sort.Strings(sortedCandidates)
+
+	best, bestDistance := "", maxTypoDistance+1
+	for _, candidate := range sortedCandidates {
+		if candidate == code {
+			continue
+		}
+		// Short codes are too similar to each other for suggestions to be useful.
+		if distance := editDistance(code, candidate); distance < bestDistance && distance < len(code)/2 {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best, best != ""
+}
+
+// typo is a declared code, which is probably a misspelling of the intended code.
+type typo struct {
+	declared, intended string
+}
+
+// findTypos pairs each unused code with the closest missing code, if any is close enough.
+// Each missing code is used at most once.
+//
+// If codes are known from the registry or from the facts of dependencies, the known codes decide which spelling is correct:
+// pairs are skipped where the unused code is known but the missing code is not,
+// as in that case the declaration is correct, and the misspelling is where the missing code is created.
+// Instead, each remaining missing code, that is not known, is paired with the closest known code.
+func findTypos(missingCodes, unusedCodes, known CodeSet) []typo {
+	remaining := Union(missingCodes, nil)
+	unused := unusedCodes.Slice()
+	sort.Strings(unused)
+
+	var result []typo
+	for _, code := range unused {
+		if intended, ok := nearestCode(code, remaining); ok {
+			if known != nil {
+				_, declaredKnown := known[code]
+				_, intendedKnown := known[intended]
+				if declaredKnown && !intendedKnown {
+					continue
+				}
+			}
+			result = append(result, typo{code, intended})
+			delete(remaining, intended)
+		}
+	}
+
+	missing := remaining.Slice()
+	sort.Strings(missing)
+	for _, code := range missing {
+		if _, ok := known[code]; ok {
+			continue
+		}
+		if intended, ok := nearestCode(code, known); ok {
+			result = append(result, typo{code, intended})
+		}
+	}
+	return result
+}
+
+// findKnownCodes finds the codes of the registry, and the codes known from the facts of dependencies:
+// the codes declared by their functions and the constant codes of their error types.
+// It returns nil, if no code is known.
+func findKnownCodes(pass *analysis.Pass) CodeSet {
+	known := Union(getPackageConfig(pass).registeredCodes, nil)
+	for _, objectFact := range pass.AllObjectFacts() {
+		if objectFact.Object.Pkg() == pass.Pkg {
+			continue
+		}
+		switch fact := objectFact.Fact.(type) {
+		case *ErrorCodes:
+			known = Union(known, fact.Codes)
+		case *ErrorType:
+			for _, code := range fact.Codes {
+				known.Add(code)
+			}
+		}
+	}
+
+	if len(known) == 0 {
+		return nil
+	}
+	return known
+}
+
+// typoMessage formats the given typos as a question to append to a diagnostic, or returns the empty string.
+func typoMessage(typos []typo) string {
+	suggestions := make([]string, 0, len(typos))
+	for _, typo := range typos {
+		suggestions = append(suggestions, fmt.Sprintf("%q instead of %q", typo.intended, typo.declared))
+	}
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %s?)", strings.Join(suggestions, ", "))
+}
+
+// typoFix creates a suggested fix replacing the misspelled codes in the declaration of the given docstring.
+// If none of the codes can be found in the docstring, nil is returned.
+func typoFix(doc *ast.CommentGroup, typos []typo) []analysis.SuggestedFix {
+	var edits []analysis.TextEdit
+	for _, typo := range typos {
+		if edit, ok := docCodeEdit(doc, typo.declared, typo.intended); ok {
+			edits = append(edits, edit)
+		}
+	}
+	if len(edits) == 0 {
+		return nil
+	}
+
+	message := "Fix misspelled error code"
+	if len(edits) > 1 {
+		message += "s"
+	}
+	return []analysis.SuggestedFix{{Message: message, TextEdits: edits}}
+}
+
+// docCommentForCode finds the comment of the given docstring, which declares the given code.
+// The second result is the offset of the code within the comment text.
+func docCommentForCode(doc *ast.CommentGroup, code string) (*ast.Comment, int) {
+	if doc == nil {
+		return nil, -1
+	}
+	for _, comment := range doc.List {
+		if offset := declaredCodeOffset(comment, code); offset >= 0 {
+			return comment, offset
+		}
+	}
+	return nil, -1
+}
+
+// declaredCodeOffset returns the offset of the given code within the text of the given comment,
+// if the comment is a line of an error code block declaring the code. Otherwise -1 is returned.
+func declaredCodeOffset(comment *ast.Comment, code string) int {
+	line := strings.TrimLeftFunc(strings.TrimPrefix(comment.Text, "//"), unicode.IsSpace)
+	if !strings.HasPrefix(line, "- ") {
+		return -1
+	}
+
+	declaration, err := parseDeclarationLine(strings.TrimRightFunc(line, unicode.IsSpace))
+	if err != nil || declaration.code != code {
+		return -1
+	}
+	return len(comment.Text) - len(line) + declaration.offset
+}
+
+// docCodeEdit creates an edit replacing the declaration of the given code in the docstring.
+func docCodeEdit(doc *ast.CommentGroup, code, replacement string) (analysis.TextEdit, bool) {
+	comment, offset := docCommentForCode(doc, code)
+	if comment == nil {
+		return analysis.TextEdit{}, false
+	}
+	pos := comment.Pos() + token.Pos(offset)
+	return analysis.TextEdit{Pos: pos, End: pos + token.Pos(len(code)), NewText: []byte(replacement)}, true
+}
+
+// codeReplacementFix creates a suggested fix replacing the given code at the given range with the replacement.
+// Fixes are created for string literals and for declarations in doc comments; for other ranges nil is returned.
+func codeReplacementFix(rng analysis.Range, code, replacement string) []analysis.SuggestedFix {
+	var edit analysis.TextEdit
+	switch node := rng.(type) {
+	case *ast.BasicLit:
+		if node.Kind != token.STRING {
+			return nil
+		}
+		edit = analysis.TextEdit{Pos: node.Pos(), End: node.End(), NewText: []byte(strconv.Quote(replacement))}
+	case *ast.Comment:
+		offset := declaredCodeOffset(node, code)
+		if offset < 0 {
+			return nil
+		}
+		pos := node.Pos() + token.Pos(offset)
+		edit = analysis.TextEdit{Pos: pos, End: pos + token.Pos(len(code)), NewText: []byte(replacement)}
+	default:
+		return nil
+	}
+	return []analysis.SuggestedFix{{Message: fmt.Sprintf("Replace with %q", replacement), TextEdits: []analysis.TextEdit{edit}}}
+}
@@ -0,0 +1,53 @@
+package analysis
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"pkg-error", "pkg-error", 0},
+		{"pkg-error", "pkg-eror", 1},
+		{"pkg-not-found", "pkg-not-fuond", 1},
+		{"pkg-error", "pkg-errors", 1},
+		{"pkg-error", "pkg-arror", 1},
+		{"pkg-error", "abc", 9},
+	}
+
+	for _, test := range tests {
+		if actual := editDistance(test.a, test.b); actual != test.distance {
+			t.Errorf("editDistance(%q, %q) should return %d but returned %d", test.a, test.b, test.distance, actual)
+		}
+	}
+}
+
+func TestNearestCode(t *testing.T) {
+	candidates := Set("pkg-not-found", "pkg-timeout", "pkg-a", "pkg-b")
+	tests := []struct {
+		code, expected string
+	}{
+		{"pkg-not-fuond", "pkg-not-found"},
+		{"pkg-timeuot", "pkg-timeout"},
+		{"pkg-timeout", ""}, // identical codes are not suggested
+		{"pkg-c", "pkg-a"},  // ties are resolved alphabetically
+		{"a-b", ""},         // too short for suggestions
+		{"pkg-permission-denied", ""},
+	}
+
+	for _, test := range tests {
+		actual, ok := nearestCode(test.code, candidates)
+		if actual != test.expected || ok != (test.expected != "") {
+			t.Errorf("nearestCode(%q) should return %q but returned %q", test.code, test.expected, actual)
+		}
+	}
+}
+
+func TestTypoSuggestedFixes(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), Analyzer, "typos/dep", "typos", "typos/registry")
+}
@@ -71,7 +71,7 @@ func findAndTagErrorTypes(pass *analysis.Pass, lookup *funcLookup) {
 			// Export error type fact for error.
 			err := tagErrorType(pass, lookup, typ, typeSpec)
 			if err != nil {
-				pass.ReportRangef(node, "%v", err)
+				reportRange(pass, categoryErrorType, node, "%v", err)
 			}
 		}
 
@@ -219,7 +219,7 @@ func analyseCodeMethod(pass *analysis.Pass, spec *ast.TypeSpec, funcDecl *ast.Fu
 		if position >= 0 {
 			field = &ErrorCodeField{fieldName.Name, position}
 		} else {
-			pass.Reportf(funcDecl.Pos(), "returned field %q is not a valid error code field (promoted fields are not supported currently, but might be added in the future)", fieldName)
+			report(pass, categoryErrorType, funcDecl.Pos(), "returned field %q is not a valid error code field (promoted fields are not supported currently, but might be added in the future)", fieldName)
 		}
 	}
 
@@ -245,9 +245,10 @@ func (state *codeMethodAnalysis) analyseReturnedExpression(node ast.Expr) {
 		if err == nil {
 			if value != "" { // Ignore empty string result of Code method.
 				state.codes.Add(value)
+				checkCreatedErrorCode(pass, node, value)
 			}
 		} else {
-			pass.ReportRangef(node, "%v", err)
+			reportRange(pass, categoryInvalidCode, node, "%v", err)
 		}
 		return
 	}
@@ -261,7 +262,7 @@ func (state *codeMethodAnalysis) analyseReturnedExpression(node ast.Expr) {
 			if state.errorCodeField == nil {
 				state.errorCodeField = expression.Sel
 			} else if state.errorCodeField.Name != expression.Sel.Name {
-				pass.ReportRangef(node, "only single field allowed: cannot return field %q because field %q was returned previously", expression.Sel.Name, state.errorCodeField.Name)
+				reportRange(pass, categoryErrorType, node, "only single field allowed: cannot return field %q because field %q was returned previously", expression.Sel.Name, state.errorCodeField.Name)
 			}
 			return
 		}
@@ -275,7 +276,7 @@ func (state *codeMethodAnalysis) analyseReturnedExpression(node ast.Expr) {
 		return
 	}
 
-	pass.ReportRangef(node, `function %q should always return a string constant or a single field`, state.funcDecl.Name.Name)
+	reportRange(pass, categoryErrorType, node, `function %q should always return a string constant or a single field`, state.funcDecl.Name.Name)
 }
 
 func (state *codeMethodAnalysis) analyseNamedReturn() {
@@ -298,11 +299,11 @@ func (state *codeMethodAnalysis) analyseReturnedIdentTaint(ident *ast.Ident) {
 	taintResult := taintSpreadForIdentOfImmutableType(state.pass, state.visited, ident, &funcDefinition{state.funcDecl, nil})
 
 	for _, badIdent := range taintResult.identOutOfScope {
-		pass.ReportRangef(badIdent, "error code variable may not be a parameter, receiver or global variable")
+		reportRange(pass, categoryErrorType, badIdent, "error code variable may not be a parameter, receiver or global variable")
 	}
 
 	for _, destruct := range taintResult.destructAssignment {
-		pass.ReportRangef(destruct.source, "unsupported: assigning result of function call to variable %q is not allowed", destruct.target.Name)
+		reportRange(pass, categoryUnsupported, destruct.source, "unsupported: assigning result of function call to variable %q is not allowed", destruct.target.Name)
 	}
 
 	for _, expr := range taintResult.expressions {
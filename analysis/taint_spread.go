@@ -19,6 +19,7 @@ type (
 		function      *funcDefinition
 		immutableType bool
 		paramIdent    *ast.Object
+		direct        bool // if set, assigned identifiers are not followed, but returned as expressions
 
 		result *taintSpreadResult
 
@@ -65,6 +66,15 @@ func taintSpreadForIdentAllowLeak(pass *analysis.Pass, visited map[*ast.Object]s
 	return ts.result
 }
 
+// taintSpreadForIdentDirect finds the values directly assigned to the given ident,
+// without following assigned identifiers. So the position of each expression is the position of its assignment.
+func taintSpreadForIdentDirect(pass *analysis.Pass, ident *ast.Ident, function *funcDefinition) *taintSpreadResult {
+	ts := newTaintSpread(pass, function, false, map[*ast.Object]struct{}{})
+	ts.direct = true
+	ts.findSpread(ident)
+	return ts.result
+}
+
 func (ts *taintSpread) findSpread(ident *ast.Ident) {
 	_, blocked := ts.blocked[ident.Obj]
 	if blocked || isIdentOriginOutsideFunctionScope(ts.function, ident) {
@@ -131,7 +141,7 @@ func (ts *taintSpread) findSpread(ident *ast.Ident) {
 func (ts *taintSpread) processAssignedExpr(expr ast.Expr) {
 	expr = astutil.Unparen(expr)
 	ident, ok := expr.(*ast.Ident)
-	if ok {
+	if ok && !ts.direct {
 		if ident.Obj != nil && ident.Obj.Kind == ast.Var {
 			ts.findSpread(ident)
 			return
@@ -5,7 +5,7 @@ import (
 )
 
 func main() {
-	One()
+	One() // want `error result of One is discarded, dropping error codes: hello-error`
 }
 
 /*
@@ -0,0 +1,46 @@
+package baseline
+
+import (
+	// Dependencies are analysed too, but are not part of the baseline.
+	_ "baselinedep"
+	_ "errors"
+)
+
+// Known has a mismatch, which is recorded in the baseline.
+//
+// Errors:
+//
+//    - baseline-error-known --
+func Known() error { // want Known:"ErrorCodes: baseline-error-known"
+	return &Error{"baseline-error-other"}
+}
+
+// Changed has a mismatch, but the baseline only knows about a different mismatch.
+//
+// Errors:
+//
+//    - baseline-error-known --
+func Changed() error { // want Changed:"ErrorCodes: baseline-error-known" `function "Changed" has a mismatch of declared and actual error codes: missing codes: \[baseline-error-new\] unused codes: \[baseline-error-known\]`
+	return &Error{"baseline-error-new"}
+}
+
+// Twice contains two unsupported expressions, but the baseline only knows about one.
+//
+// Errors: none
+func Twice(a, b interface{}) error { // want Twice:"ErrorCodes: "
+	if a != nil {
+		return a.(error)
+	}
+	return b.(error) // want "type assertion is not supported in error code analysis"
+}
+
+func Undeclared() error {
+	return nil
+}
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"TheCode", Position:0}, Codes:}`
+	TheCode string
+}
+
+func (e *Error) Code() string  { return e.TheCode }
+func (e *Error) Error() string { return e.TheCode }
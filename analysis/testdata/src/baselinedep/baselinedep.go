@@ -0,0 +1,6 @@
+package baselinedep
+
+// Open is part of another module, so its missing declaration is not recorded in the baseline of the importing package.
+func Open() error {
+	return nil
+}
@@ -0,0 +1,3 @@
+module baselinedep
+
+go 1.17
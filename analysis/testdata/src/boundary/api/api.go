@@ -0,0 +1,81 @@
+package api
+
+import "boundary/db"
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// Get leaks a code of the database layer.
+//
+// Errors:
+//
+//    - api-not-found -- if nothing was found
+//    - db-conflict -- if a concurrent update happened
+//    - db-timeout -- if the database does not respond in time
+func Get(n int) error { // want Get:"ErrorCodes: api-not-found db-conflict db-timeout" `function "Get" declares error code "db-conflict", which must not cross the boundary of package "boundary/api" \(forbidden by "db-\*"\): translate them into api codes; introduced by: db.Query, query` `function "Get" declares error code "db-timeout", which .* introduced by: db.Query$`
+	if n == 0 {
+		return &Error{"api-not-found"}
+	}
+	if n == 1 {
+		return query(n)
+	}
+	return db.Query(n)
+}
+
+// Put translates the codes of the database layer.
+//
+// Errors:
+//
+//    - api-unavailable -- if the database is not available
+func Put(n int) error { // want Put:"ErrorCodes: api-unavailable"
+	if err := db.Query(n); err != nil {
+		return &Error{"api-unavailable"}
+	}
+	return nil
+}
+
+// Fake creates a code of the database layer itself.
+//
+// Errors:
+//
+//    - db-timeout --
+func Fake() error { // want Fake:"ErrorCodes: db-timeout" `function "Fake" declares error code "db-timeout", which must not cross the boundary of package "boundary/api" \(forbidden by "db-\*"\): translate them into api codes; introduced by: this function`
+	return &Error{"db-timeout"}
+}
+
+// query is not exported, so it may return any codes.
+//
+// Errors:
+//
+//    - db-conflict --
+func query(n int) error { // want query:"ErrorCodes: db-conflict"
+	if err := db.Query(n); err != nil {
+		return &Error{"db-conflict"}
+	}
+	return nil
+}
+
+type Store interface { // want Store:"ErrorInterface: Load"
+	// Load leaks a code of the database layer.
+	//
+	// Errors:
+	//
+	//    - db-timeout --
+	Load() error // want Load:"ErrorCodes: db-timeout" `interface method "Load" declares error code "db-timeout", which must not cross the boundary of package "boundary/api" \(forbidden by "db-\*"\): translate them into api codes`
+}
+
+type cache struct{}
+
+// Get may return codes of the database layer, because its receiver type is not exported.
+//
+// Errors:
+//
+//    - db-conflict --
+//    - db-timeout --
+func (cache) Get(n int) error { // want Get:"ErrorCodes: db-conflict db-timeout"
+	return db.Query(n)
+}
@@ -0,0 +1,13 @@
+package internal
+
+import "boundary/db"
+
+// Get is not part of a boundary, because the pattern of the boundary does not match sub packages.
+//
+// Errors:
+//
+//    - db-conflict --
+//    - db-timeout --
+func Get(n int) error { // want Get:"ErrorCodes: db-conflict db-timeout"
+	return db.Query(n)
+}
@@ -0,0 +1,21 @@
+package db
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// Query runs a query.
+//
+// Errors:
+//
+//    - db-timeout -- if the database does not respond in time
+//    - db-conflict -- if a concurrent update happened
+func Query(n int) error { // want Query:"ErrorCodes: db-conflict db-timeout"
+	if n == 0 {
+		return &Error{"db-timeout"}
+	}
+	return &Error{"db-conflict"}
+}
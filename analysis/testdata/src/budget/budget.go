@@ -0,0 +1,88 @@
+package budget
+
+import "budget/relaxed"
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// Small stays within the budget.
+//
+// Errors:
+//
+//    - budget-a --
+//    - budget-b --
+func Small(n int) error { // want Small:"ErrorCodes: budget-a budget-b"
+	if n == 0 {
+		return &Error{"budget-a"}
+	}
+	return &Error{"budget-b"}
+}
+
+// Large exceeds the budget.
+//
+// Errors:
+//
+//    - budget-a --
+//    - budget-b --
+//    - budget-c --
+//    - relaxed-a --
+//    - relaxed-b --
+//    - relaxed-c --
+func Large(n int) error { // want Large:"ErrorCodes: budget-a budget-b budget-c relaxed-a relaxed-b relaxed-c" `function "Large" declares 6 error codes, more than the limit of 2: budget-a budget-b budget-c relaxed-a relaxed-b relaxed-c; most codes come from: relaxed.Many \(3\), Small \(2\), helper \(1\)`
+	switch n {
+	case 0:
+		return Small(n)
+	case 1:
+		return relaxed.Many(n)
+	}
+	return helper()
+}
+
+// large exceeds the budget, but is not exported.
+//
+// Errors:
+//
+//    - budget-a --
+//    - budget-b --
+//    - budget-c --
+func large(n int) error { // want large:"ErrorCodes: budget-a budget-b budget-c"
+	if n == 0 {
+		return Small(n)
+	}
+	return helper()
+}
+
+func helper() error {
+	return &Error{"budget-c"}
+}
+
+type Store interface { // want Store:"ErrorInterface: Get"
+	// Get exceeds the budget.
+	//
+	// Errors:
+	//
+	//    - budget-a --
+	//    - budget-b --
+	//    - budget-c --
+	Get() error // want Get:"ErrorCodes: budget-a budget-b budget-c" `interface method "Get" declares 3 error codes, more than the limit of 2: budget-a budget-b budget-c`
+}
+
+type store struct{}
+
+// Get exceeds the budget, but its receiver type is not exported.
+//
+// Errors:
+//
+//    - budget-a --
+//    - budget-b --
+//    - budget-c --
+func (store) Get(n int) error { // want Get:"ErrorCodes: budget-a budget-b budget-c"
+	if n == 0 {
+		return Small(n)
+	}
+	return helper()
+}
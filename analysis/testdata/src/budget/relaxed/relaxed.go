@@ -0,0 +1,25 @@
+package relaxed
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// Many is not limited.
+//
+// Errors:
+//
+//    - relaxed-a --
+//    - relaxed-b --
+//    - relaxed-c --
+func Many(n int) error { // want Many:"ErrorCodes: relaxed-a relaxed-b relaxed-c"
+	switch n {
+	case 0:
+		return &Error{"relaxed-a"}
+	case 1:
+		return &Error{"relaxed-b"}
+	}
+	return &Error{"relaxed-c"}
+}
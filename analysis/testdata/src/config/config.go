@@ -0,0 +1,40 @@
+package config
+
+import "config/external"
+
+func Undeclared() error { // want `function "Undeclared" is exported, but does not declare any error codes`
+	return nil
+}
+
+// CallAllowedPackage calls a function of a package which is allowed to not declare error codes.
+//
+// Errors:
+//
+//    - config-error -- is always returned
+func CallAllowedPackage() error { // want CallAllowedPackage:"ErrorCodes: config-error"
+	if err := external.Do(); err != nil {
+		return external.Do()
+	}
+	return &Error{"config-error"}
+}
+
+// CallAllowedMethod calls a method of a type in a package which is allowed to not declare error codes.
+//
+// Errors: none -- the error is not a serum error.
+func CallAllowedMethod() error { // want CallAllowedMethod:"ErrorCodes: "
+	return external.Type{}.Do()
+}
+
+// UnsupportedDisabled contains an unsupported expression, but the category is disabled.
+//
+// Errors: none -- the error is not a serum error.
+func UnsupportedDisabled(value interface{}) error { // want UnsupportedDisabled:"ErrorCodes: "
+	return value.(error)
+}
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"TheCode", Position:0}, Codes:}`
+	TheCode string
+}
+
+func (e *Error) Code() string  { return e.TheCode }
+func (e *Error) Error() string { return e.TheCode }
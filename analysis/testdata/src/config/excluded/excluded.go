@@ -0,0 +1,14 @@
+package excluded
+
+// Excluded has a mismatch, which is not reported because the package is excluded.
+//
+// Errors:
+//
+//    - excluded-error --
+func Excluded() error { // want Excluded:"ErrorCodes: excluded-error"
+	return nil
+}
+
+func Undeclared() error {
+	return nil
+}
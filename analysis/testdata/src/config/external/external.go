@@ -0,0 +1,13 @@
+package external
+
+import "errors"
+
+func Do() error {
+	return errors.New("external error")
+}
+
+type Type struct{}
+
+func (Type) Do() error {
+	return errors.New("external error")
+}
@@ -0,0 +1,5 @@
+package generated
+
+func HandwrittenUndeclared() error { // want `function "HandwrittenUndeclared" is exported, but does not declare any error codes`
+	return nil
+}
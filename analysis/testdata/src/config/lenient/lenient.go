@@ -0,0 +1,6 @@
+package lenient
+
+// Undeclared is not reported, because strict mode is disabled for this package.
+func Undeclared() error {
+	return nil
+}
@@ -0,0 +1,40 @@
+package naming
+
+// Naming creates errors with codes that have to match the configured pattern.
+//
+// Errors:
+//
+//    - naming-error-valid --
+//    - invalid-error      --
+//    - naming-error-field --
+//    - naming-error-const --
+func Naming(i int) error { // want Naming:"ErrorCodes: invalid-error naming-error-const naming-error-field naming-error-valid"
+	switch i {
+	case 0:
+		return &Error{"naming-error-valid"}
+	case 1:
+		return &Error{"invalid-error"} // want `error code "invalid-error" does not match the pattern "\^naming-" required for codes of package "config/naming"`
+	case 2:
+		err := &Error{}
+		err.TheCode = "naming-error-field"
+		return err
+	}
+	return &ConstError{}
+}
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"TheCode", Position:0}, Codes:}`
+	TheCode string
+}
+
+func (e *Error) Code() string  { return e.TheCode }
+func (e *Error) Error() string { return e.TheCode }
+
+type ConstError struct{} // want ConstError:`ErrorType{Field:<nil>, Codes:naming-error-const}`
+
+func (e *ConstError) Code() string  { return "naming-error-const" }
+func (e *ConstError) Error() string { return "naming-error-const" }
+
+type BadConstError struct{} // want BadConstError:`ErrorType{Field:<nil>, Codes:bad-const}`
+
+func (e *BadConstError) Code() string  { return "bad-const" } // want `error code "bad-const" does not match the pattern "\^naming-" required for codes of package "config/naming"`
+func (e *BadConstError) Error() string { return "bad-const" }
@@ -0,0 +1,43 @@
+package prefix
+
+import "config/naming"
+
+// Prefix creates errors with codes that have to start with the prefix derived from the import path.
+//
+// Errors:
+//
+//    - config-prefix-valid   --
+//    - prefix-invalid        --
+//    - config-prefix-const   --
+//    - naming-error-valid    -- propagated codes of other packages are not checked
+//    - invalid-error         --
+//    - naming-error-field    --
+//    - naming-error-const    --
+func Prefix(i int) error { // want Prefix:"ErrorCodes: config-prefix-const config-prefix-valid invalid-error naming-error-const naming-error-field naming-error-valid prefix-invalid"
+	switch i {
+	case 0:
+		return &Error{"config-prefix-valid"}
+	case 1:
+		return &Error{"prefix-invalid"} // want `error code "prefix-invalid" does not start with the prefix "config-prefix-" required for codes of package "config/prefix"`
+	case 2:
+		return naming.Naming(i)
+	}
+	return &ConstError{}
+}
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"TheCode", Position:0}, Codes:}`
+	TheCode string
+}
+
+func (e *Error) Code() string  { return e.TheCode }
+func (e *Error) Error() string { return e.TheCode }
+
+type ConstError struct{} // want ConstError:`ErrorType{Field:<nil>, Codes:config-prefix-const}`
+
+func (e *ConstError) Code() string  { return "config-prefix-const" }
+func (e *ConstError) Error() string { return "config-prefix-const" }
+
+type BadConstError struct{} // want BadConstError:`ErrorType{Field:<nil>, Codes:bad-const}`
+
+func (e *BadConstError) Code() string  { return "bad-const" } // want `error code "bad-const" does not start with the prefix "config-prefix-" required for codes of package "config/prefix"`
+func (e *BadConstError) Error() string { return "bad-const" }
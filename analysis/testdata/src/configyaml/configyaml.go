@@ -0,0 +1,15 @@
+package configyaml
+
+// Undeclared is not reported, because strict mode is disabled in the configuration.
+func Undeclared() error {
+	return nil
+}
+
+// Mismatch is not reported, because mismatches are disabled in the configuration.
+//
+// Errors:
+//
+//    - configyaml-error --
+func Mismatch() error { // want Mismatch:"ErrorCodes: configyaml-error"
+	return nil
+}
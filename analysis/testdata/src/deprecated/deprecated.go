@@ -0,0 +1,81 @@
+package deprecated
+
+import "deprecated/legacy"
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+const codeOld = "legacy-old"
+
+// Code returns the error code of the given error.
+func Code(err error) string {
+	if e, ok := err.(*Error); ok {
+		return e.code
+	}
+	return ""
+}
+
+// Pass passes the deprecated codes of legacy.Load on, which is allowed.
+//
+// Errors:
+//
+//    - legacy-ancient --
+//    - legacy-new     --
+//    - legacy-old     --
+func Pass(n int) error { // want Pass:"ErrorCodes: \\(deprecated\\) legacy-ancient legacy-new \\(deprecated\\) legacy-old"
+	return legacy.Load(n)
+}
+
+// Create starts to return a deprecated code.
+//
+// Errors:
+//
+//    - legacy-old --
+func Create() error { // want Create:"ErrorCodes: \\(deprecated\\) legacy-old" `function "Create" returns deprecated error code "legacy-old", use "legacy-new" instead`
+	return &Error{"legacy-old"}
+}
+
+// Handle checks for the deprecated codes.
+//
+// Errors: none
+func Handle(n int) error { // want Handle:"ErrorCodes:"
+	err := legacy.Load(n)
+	if err != nil && Code(err) == "legacy-old" { // want `comparison with deprecated error code "legacy-old", use "legacy-new" instead`
+		return nil
+	}
+	if Code(err) != "legacy-new" {
+		return nil
+	}
+
+	switch Code(err) {
+	case codeOld: // want `comparison with deprecated error code "legacy-old", use "legacy-new" instead`
+		return nil
+	case "legacy-new", "legacy-ancient": // want `comparison with deprecated error code "legacy-ancient"$`
+		return nil
+	}
+
+	switch {
+	case Code(err) == "legacy-ancient": // want `comparison with deprecated error code "legacy-ancient"$`
+		return nil
+	}
+	return nil
+}
+
+// Other compares strings, that are not error codes.
+func Other(name string, err *Error) bool {
+	if name == "legacy-old" || codeOld == name {
+		return true
+	}
+	switch name {
+	case codeOld:
+		return true
+	}
+	if err.Code() == codeOld { // want `comparison with deprecated error code "legacy-old", use "legacy-new" instead`
+		return true
+	}
+	return false
+}
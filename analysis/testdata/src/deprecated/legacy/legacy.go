@@ -0,0 +1,34 @@
+package legacy
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// Load is an existing origin of the deprecated codes.
+//
+// Errors:
+//
+//    - legacy-old     -- (deprecated: use legacy-new) if the old thing happens
+//    - legacy-ancient -- (deprecated)
+//    - legacy-new     -- if the new thing happens
+func Load(n int) error { // want Load:"ErrorCodes: \\(deprecated\\) legacy-ancient legacy-new \\(deprecated\\) legacy-old"
+	switch n {
+	case 0:
+		return &Error{"legacy-old"}
+	case 1:
+		return &Error{"legacy-ancient"}
+	}
+	return &Error{"legacy-new"}
+}
+
+// Odd has a malformed deprecation.
+//
+// Errors:
+//
+//    - legacy-old -- (deprecated: legacy-new)
+func Odd() error { // want `function "Odd" has odd docstring: a deprecation has to be formatted as '\(deprecated\)' or '\(deprecated: use <code>\)'`
+	return &Error{"legacy-old"}
+}
@@ -0,0 +1,107 @@
+package discarded
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// One returns an error.
+//
+// Errors:
+//
+//   - discarded-one --
+func One() error { // want One:"ErrorCodes: discarded-one"
+	return &Error{"discarded-one"}
+}
+
+// Two returns a value and an error.
+//
+// Errors:
+//
+//   - discarded-a --
+//   - discarded-b --
+func Two(n int) (int, error) { // want Two:"ErrorCodes: discarded-a discarded-b"
+	if n == 0 {
+		return 0, &Error{"discarded-a"}
+	}
+	return n, &Error{"discarded-b"}
+}
+
+// Never only returns an error to comply with an interface.
+//
+// Errors: none
+func Never() error { // want Never:"ErrorCodes:"
+	return nil
+}
+
+func undocumented() error {
+	return nil
+}
+
+func statements() {
+	One()   // want `error result of One is discarded, dropping error codes: discarded-one`
+	(One()) // want `error result of One is discarded, dropping error codes: discarded-one`
+	Never()
+	undocumented()
+	go One()    // want `error result of One is discarded by the go statement, dropping error codes: discarded-one`
+	defer One() // want `error result of One is discarded by the defer statement, dropping error codes: discarded-one`
+	go Never()
+	defer func() {
+		_ = One() // want `error result of One is assigned to the blank identifier, dropping error codes: discarded-one`
+	}()
+}
+
+func blank() int {
+	_ = One()       // want `error result of One is assigned to the blank identifier, dropping error codes: discarded-one`
+	n, _ := Two(1)  // want `error result of Two is assigned to the blank identifier, dropping error codes: discarded-a discarded-b`
+	_, _ = n, One() // want `error result of One is assigned to the blank identifier, dropping error codes: discarded-one`
+	_ = Never()
+	return n
+}
+
+// Overwrite overwrites errors before checking them.
+//
+// Errors:
+//
+//   - discarded-a --
+//   - discarded-b --
+//   - discarded-one --
+func Overwrite(check bool) error { // want Overwrite:"ErrorCodes: discarded-a discarded-b discarded-one"
+	err := One() // want `error result of One is overwritten before it is checked, dropping error codes: discarded-one`
+	_, err = Two(1)
+	if err != nil {
+		return err
+	}
+
+	err = One() // read below, so it is not reported
+	if check {
+		err = One() // the error of the previous call might not be overwritten
+	}
+	if err != nil {
+		return err
+	}
+
+	var n int
+	n, err = Two(n) // want `error result of Two is overwritten before it is checked, dropping error codes: discarded-a discarded-b`
+	n++
+	err = One()
+	if err != nil {
+		return err
+	}
+
+	err = One()
+	err = wrap(err) // reads the error before overwriting it
+	return err
+}
+
+// wrap returns the given error.
+//
+// Errors:
+//
+//   - discarded-one --
+func wrap(err error) error { // want wrap:"ErrorCodes: discarded-one"
+	// Error Codes = discarded-one
+	return err
+}
@@ -63,7 +63,7 @@ func SubtractCode() error { // want SubtractCode:"ErrorCodes: examples-error-thr
 		return nil
 	default:
 		// Error Codes -= examples-error-one
-		return err
+		return err // want `variable "err" of type \*Error may be nil, which is returned as a non-nil error`
 	}
 }
 
@@ -74,7 +74,7 @@ func SubtractCode() error { // want SubtractCode:"ErrorCodes: examples-error-thr
 func AddSubCode() error { // want AddSubCode:"ErrorCodes: examples-error-extra examples-error-one"
 	err := MultipleCodes()
 	// Error Codes -examples-error-two -examples-error-three +examples-error-extra
-	return err
+	return err // want `variable "err" of type \*Error may be nil, which is returned as a non-nil error`
 }
 
 // AssignmentProblem showcases error code field assignment not being
@@ -55,7 +55,7 @@ func (b *BoxImpl) Pop() (interface{}, error) { // want Pop:"ErrorCodes: examples
 // showing that it can be used that way.
 func UseBoxImplAsBox() {
 	var b Box = &BoxImpl{}
-	b.Put(b)
+	b.Put(b) // want `error result of Box.Put is discarded, dropping error codes: examples-error-arg-nil examples-error-invalid examples-error-unknown`
 }
 
 type BoxInvalidImpl struct{}
@@ -81,7 +81,7 @@ func UseBoxInvalidImplAsBox() {
 		want
 			`cannot use expression as "Box" value: method "Put" declares the following error codes which were not part of the interface: \[examples-error-not-implemented]`
 			`cannot use expression as "Box" value: method "Pop" declares the following error codes which were not part of the interface: \[examples-error-not-implemented]` */
-	b.Put(b)
+	b.Put(b) // want `error result of Box.Put is discarded, dropping error codes: examples-error-arg-nil examples-error-invalid examples-error-unknown`
 }
 
 type Box2 interface { // want Box2:"ErrorInterface: Pop Put"
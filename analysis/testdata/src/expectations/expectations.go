@@ -0,0 +1,30 @@
+package expectations
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// Open opens something.
+//
+// Errors:
+//
+//    - expectations-missing --
+//    - expectations-denied --
+func Open(name string) (int, error) { // want Open:"ErrorCodes: expectations-denied expectations-missing"
+	if name == "" {
+		return 0, &Error{"expectations-missing"}
+	}
+	return 0, &Error{"expectations-denied"}
+}
+
+// Close closes something.
+//
+// Errors:
+//
+//    - expectations-busy --
+func Close(n int) error { // want Close:"ErrorCodes: expectations-busy"
+	return &Error{"expectations-busy"}
+}
@@ -0,0 +1,54 @@
+package expectations
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/serum-errors/go-serum-analyzer/serumtest"
+)
+
+const codeGone = "expectations-gone"
+
+func TestOpen(t *testing.T) {
+	_, err := Open("")
+	serumtest.ExpectCode(t, err, "expectations-missing")
+	serumtest.ExpectCode(t, err, "expectations-busy") // want `test expects error code "expectations-busy", but Open only declares \[expectations-denied expectations-missing\]`
+}
+
+func TestClose(t *testing.T) {
+	serumtest.ExpectCode(t, Close(1), "expectations-busy")
+	serumtest.ExpectCode(t, Close(1), codeGone) // want `test expects error code "expectations-gone", but Close only declares \[expectations-busy\]`
+
+	var err error
+	if testing.Short() {
+		_, err = Open("x")
+	} else {
+		err = Close(1)
+	}
+	serumtest.ExpectCode(t, err, "expectations-denied")
+	serumtest.ExpectCode(t, err, "expectations-full") // want `test expects error code "expectations-full", but Open, Close only declare \[expectations-busy expectations-denied expectations-missing\]`
+}
+
+func TestUnknown(t *testing.T) {
+	err := errors.New("plain")
+	serumtest.ExpectCode(t, err, "expectations-other") // the source of the error is unknown
+}
+
+func TestOpenErrors(t *testing.T) {
+	tests := []struct {
+		code        string
+		description string
+	}{
+		{code: "expectations-missing", description: "if the file does not exist"},
+		{"expectations-denied", ""},
+		{code: "expectations-busy"}, // want `test expects error code "expectations-busy", but Open only declares \[expectations-denied expectations-missing\]`
+		{description: "without code"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.code, func(t *testing.T) {
+			_, err := Open(test.description)
+			serumtest.ExpectCode(t, err, test.code)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// Code returns the code of the given error.
+func Code(err error) string {
+	if e, ok := err.(*Error); ok {
+		return e.code
+	}
+	return ""
+}
+
+// One returns a single code.
+//
+// Errors:
+//
+//    - handlers-a --
+func One() *Error { // want One:"ErrorCodes: handlers-a"
+	return &Error{"handlers-a"}
+}
+
+// Two returns two codes.
+//
+// Errors:
+//
+//    - handlers-a --
+//    - handlers-b --
+func Two(n int) error { // want Two:"ErrorCodes: handlers-a handlers-b"
+	if n == 0 {
+		return &Error{"handlers-a"}
+	}
+	return &Error{"handlers-b"}
+}
+
+const codeGone = "handlers-gone"
+
+func conditions() {
+	if err := One(); err != nil && err.Code() == "handlers-b" { // want `handler for code "handlers-b" can never trigger; One returns \[handlers-a\]`
+		return
+	}
+
+	err := Two(1)
+	if Code(err) == "handlers-a" || codeGone == Code(err) { // want `handler for code "handlers-gone" can never trigger; Two returns \[handlers-a handlers-b\]`
+		return
+	}
+	if err.(*Error).Code() != "handlers-c" { // want `handler for code "handlers-c" can never trigger; Two returns \[handlers-a handlers-b\]`
+		return
+	}
+}
+
+func switches(n int) {
+	var err error
+	if n == 0 {
+		err = One()
+	} else {
+		err = Two(n)
+	}
+
+	switch Code(err) {
+	case "handlers-a", "handlers-b":
+	case "": // not a code, but returned by Code if err is nil
+	case codeGone: // want `handler for code "handlers-gone" can never trigger; One, Two return \[handlers-a handlers-b\]`
+	}
+}
+
+// statusCodes is shared by all callers, so its codes don't have to be returned by every lookup.
+var statusCodes = map[string]int{
+	"handlers-a": 400,
+	"handlers-c": 404,
+}
+
+func lookup() int {
+	handlers := map[string]func(){
+		"handlers-a": func() {},
+		"handlers-c": func() {},
+	}
+
+	err := Two(1)
+	if handler, ok := handlers[Code(err)]; ok { // want `handler for code "handlers-c" can never trigger; Two returns \[handlers-a handlers-b\]`
+		handler()
+	}
+	return statusCodes[Code(err)]
+}
+
+func unknown(param error) {
+	if Code(param) == "handlers-c" { // parameters may have any code
+		return
+	}
+
+	err := Two(1)
+	err = wrap(err)
+	if Code(err) == "handlers-c" { // wrap does not declare error codes
+		return
+	}
+}
+
+func wrap(err error) error {
+	return err
+}
@@ -0,0 +1,16 @@
+package infer
+
+import "infer/inner"
+
+// Run runs something.
+//
+// Errors:
+//
+//    - inner-invalid -- if the name is empty
+//    - inner-not-found -- if nothing was found
+func Run(name string) error { // want Run:"ErrorCodes: inner-invalid inner-not-found"
+	if err := inner.Close(); err != nil {
+		return err
+	}
+	return inner.Open(name)
+}
@@ -0,0 +1,24 @@
+package inner
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// Open opens something.
+func Open(name string) error { // want Open:"ErrorCodes \\(inferred\\): inner-invalid inner-not-found" `function "Open" is exported, but does not declare any error codes`
+	if name == "" {
+		return invalid()
+	}
+	return &Error{"inner-not-found"}
+}
+
+func Close() error { // want Close:"ErrorCodes \\(inferred\\): " `function "Close" is exported, but does not declare any error codes`
+	return nil
+}
+
+func invalid() error { // want invalid:"ErrorCodes \\(inferred\\): inner-invalid"
+	return &Error{"inner-invalid"}
+}
@@ -0,0 +1,21 @@
+package retry
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// Do may ask its caller to try again. It is exported, but the package is internal.
+//
+// Errors:
+//
+//    - (internal) retry-again -- if the operation should be retried
+//    - retry-failed -- if the operation failed permanently
+func Do(n int) error { // want Do:"ErrorCodes: \\(internal\\) retry-again retry-failed"
+	if n == 0 {
+		return &Error{"retry-again"}
+	}
+	return &Error{"retry-failed"}
+}
@@ -0,0 +1,88 @@
+package internalcodes
+
+import "internalcodes/internal/retry"
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// Get propagates the internal code of retry.Do, without marking it as internal.
+//
+// Errors:
+//
+//    - retry-again --
+//    - retry-failed --
+func Get(n int) error { // want Get:"ErrorCodes: \\(internal\\) retry-again retry-failed" `function "Get" declares internal error code "retry-again", which must not escape the public package "internalcodes"; introduced by: retry.Do`
+	return retry.Do(n)
+}
+
+// Put handles the internal code of retry.Do.
+//
+// Errors:
+//
+//    - store-unavailable --
+func Put(n int) error { // want Put:"ErrorCodes: store-unavailable"
+	if err := retry.Do(n); err != nil {
+		return &Error{"store-unavailable"}
+	}
+	return nil
+}
+
+// Sync declares an internal code itself.
+//
+// Errors:
+//
+//    - (internal) store-busy --
+func Sync() error { // want Sync:"ErrorCodes: \\(internal\\) store-busy" `function "Sync" declares internal error code "store-busy", which must not escape the public package "internalcodes"; introduced by: this function`
+	return &Error{"store-busy"}
+}
+
+// Flush gets an internal code from an unexported function.
+//
+// Errors:
+//
+//    - store-busy --
+func Flush() error { // want Flush:"ErrorCodes: \\(internal\\) store-busy" `function "Flush" declares internal error code "store-busy", .* introduced by: flush`
+	return flush()
+}
+
+// flush is not exported, so it may return internal codes.
+//
+// Errors:
+//
+//    - store-busy --
+func flush() error { // want flush:"ErrorCodes: \\(internal\\) store-busy"
+	return &Error{"store-busy"}
+}
+
+type Store interface { // want Store:"ErrorInterface: Load"
+	// Load declares an internal code.
+	//
+	// Errors:
+	//
+	//    - (internal) store-busy --
+	Load() error // want Load:"ErrorCodes: \\(internal\\) store-busy" `interface method "Load" declares internal error code "store-busy", which must not escape the public package "internalcodes"`
+}
+
+// Odd uses an unknown attribute.
+//
+// Errors:
+//
+//    - (secret) store-busy --
+func Odd() error { // want `function "Odd" has odd docstring: unknown error code attribute "secret"`
+	return &Error{"store-busy"}
+}
+
+type buffer struct{}
+
+// Flush may return internal codes, because its receiver type is not exported.
+//
+// Errors:
+//
+//    - store-busy --
+func (*buffer) Flush() error { // want Flush:"ErrorCodes: \\(internal\\) store-busy"
+	return flush()
+}
@@ -0,0 +1,73 @@
+package registry
+
+// Lookup declares a code, that is not registered.
+//
+// Errors:
+//
+//    - registry-not-found --
+//    - registry-not-fuond -- a typo // want `error code "registry-not-fuond" is not registered, did you mean "registry-not-found"\?`
+func Lookup(i int) error { // want Lookup:"ErrorCodes: registry-not-found registry-not-fuond"
+	if i == 0 {
+		return NewError("registry-not-found")
+	}
+	return NewError("registry-not-fuond") // want `error code "registry-not-fuond" is not registered, did you mean "registry-not-found"\?`
+}
+
+// Annotated returns codes defined by an annotation.
+//
+// Errors:
+//
+//    - registry-invalid --
+//    - registry-missing -- // want `error code "registry-missing" is not registered$`
+func Annotated() error { // want Annotated:"ErrorCodes: registry-invalid registry-missing"
+	// Error Codes = registry-invalid, registry-missing
+	return unknown() // want `error code "registry-missing" is not registered$`
+}
+
+// Constant returns an error type with a constant code.
+//
+// Errors:
+//
+//    - registry-constant --
+//    - registry-old      --
+func Constant(old bool) error { // want Constant:"ErrorCodes: registry-constant \\(deprecated\\) registry-old" `function "Constant" returns deprecated error code "registry-old", use "registry-constant" instead`
+	if old {
+		return &OldError{}
+	}
+	return &ConstError{}
+}
+
+// NewError creates a new error with the given code.
+//
+// Errors:
+//
+//    - param: code --
+func NewError(code string) error { // want NewError:"ErrorConstructor: {CodeParamPosition:0}" NewError:"ErrorCodes:"
+	return &Error{code}
+}
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"TheCode", Position:0}, Codes:}`
+	TheCode string
+}
+
+func (e *Error) Code() string  { return e.TheCode }
+func (e *Error) Error() string { return e.TheCode }
+
+type ConstError struct{} // want ConstError:`ErrorType{Field:<nil>, Codes:registry-constant}`
+
+func (e *ConstError) Code() string  { return "registry-constant" }
+func (e *ConstError) Error() string { return "registry-constant" }
+
+type OldError struct{} // want OldError:`ErrorType{Field:<nil>, Codes:registry-old}`
+
+func (e *OldError) Code() string  { return "registry-old" }
+func (e *OldError) Error() string { return "registry-old" }
+
+type UnknownError struct{} // want UnknownError:`ErrorType{Field:<nil>, Codes:registry-unknown}`
+
+func (e *UnknownError) Code() string  { return "registry-unknown" } // want `error code "registry-unknown" is not registered$`
+func (e *UnknownError) Error() string { return "registry-unknown" }
+
+func unknown() error {
+	return nil
+}
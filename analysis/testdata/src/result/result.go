@@ -0,0 +1,88 @@
+package result
+
+import "typecast"
+
+type Error struct {
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// Find finds something.
+//
+// Errors:
+//
+//    - result-not-found -- if nothing was found
+//    - result-invalid -- if the query is invalid
+func Find(query string) error { // want `doc: codes` `sources: result.Error: result-invalid result-not-found`
+	if query == "" {
+		return &Error{"result-invalid"} // want `error type result.Error: field code` `return: result-invalid`
+	}
+	return &Error{"result-not-found"} // want `error type result.Error: field code` `return: result-not-found`
+}
+
+// Lookup looks up something.
+//
+// Errors:
+//
+//    - result-not-found -- if nothing was found
+//    - result-invalid -- if the query is invalid
+//    - string-error -- if the type cast fails
+func Lookup(query string) error { // want `doc: codes` `sources: result.Find: result-not-found; typecast.TypeCast: string-error`
+	if query == "cast" {
+		return typecast.TypeCast() // want `call typecast.TypeCast: string-error` `return: string-error`
+	}
+
+	// Error Codes -= result-invalid
+	return Find(query) // want `call result.Find: result-invalid result-not-found` `return: result-not-found`
+}
+
+// Fetch fetches something.
+//
+// Errors:
+//
+//    - result-not-found -- if nothing was found
+//    - result-invalid -- if the query is invalid
+func Fetch(query string) error { // want `doc: codes` `sources: result.Error: result-invalid; result.Find: result-not-found`
+	if err := Find(""); err != nil { // want `call result.Find: result-invalid result-not-found`
+		return &Error{"result-invalid"} // want `error type result.Error: field code` `return: result-invalid`
+	}
+	return find(query) // want `return: result-not-found`
+}
+
+func find(query string) error { // want `doc: undocumented` `sources: result.Find: result-not-found`
+	// Error Codes -= result-invalid
+	return Find(query) // want `call result.Find: result-invalid result-not-found` `return: result-not-found`
+}
+
+func Undocumented() error { // want `doc: undocumented`
+	err := Lookup("") // want `call result.Lookup: result-invalid result-not-found string-error`
+	return err
+}
+
+// Check checks nothing.
+//
+// Errors: none
+func Check() error { // want `doc: none`
+	return nil
+}
+
+func NoError() {}
+
+type Store interface {
+	// Get gets something.
+	//
+	// Errors:
+	//
+	//    - result-not-found -- if nothing was found
+	Get() error // want `doc: codes`
+
+	Put() error // want `doc: undocumented`
+}
+
+var (
+	_ = typecast.StringError("") // want `error type typecast.StringError: string-error`
+	_ = &Error{}                 // want `error type result.Error: field code`
+	_ = Error{}                  // want `error type result.Error: field code`
+)
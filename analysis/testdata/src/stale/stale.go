@@ -0,0 +1,82 @@
+package stale
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// a fails first.
+//
+// Errors:
+//
+//    - stale-a --
+func a() (int, error) { // want a:"ErrorCodes: stale-a"
+	return 0, &Error{"stale-a"}
+}
+
+// b fails second.
+//
+// Errors:
+//
+//    - stale-b --
+func b() (int, error) { // want b:"ErrorCodes: stale-b"
+	return 0, &Error{"stale-b"}
+}
+
+// Init returns the error of a, when b fails.
+//
+// Errors:
+//
+//    - stale-a --
+func Init() error { // want Init:"ErrorCodes: stale-a"
+	x, err := a()
+	if err != nil {
+		return err
+	}
+	if y, err2 := b(); err2 != nil {
+		return err // want `returned error "err" was assigned by the earlier call to a, but the checked error "err2" comes from b`
+	} else {
+		x += y
+	}
+	return nil
+}
+
+// NotUpdated does not assign the error of b to err.
+//
+// Errors:
+//
+//    - stale-a --
+//    - stale-b --
+func NotUpdated() error { // want NotUpdated:"ErrorCodes: stale-a stale-b"
+	_, err := a()
+	_, bErr := b()
+	if bErr != nil && x() {
+		return err // want `returned error "err" was assigned by the earlier call to a, but the checked error "bErr" comes from b`
+	}
+	if bErr != nil {
+		err = bErr // updated, so this is fine
+		return err
+	}
+	return nil
+}
+
+// Fine returns the checked errors.
+//
+// Errors:
+//
+//    - stale-a --
+func Fine() error { // want Fine:"ErrorCodes: stale-a"
+	_, bErr := b()
+	_, err := a()
+	if bErr != nil {
+		return err // err was assigned after bErr, so it is the more recent error
+	}
+	if err != nil || bErr != nil {
+		return err
+	}
+	return nil
+}
+
+func x() bool { return true }
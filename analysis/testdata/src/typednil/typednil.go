@@ -0,0 +1,153 @@
+package typednil
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+type NotCoded struct{}
+
+func (e *NotCoded) Error() string { return "not coded" }
+
+// find returns nil, if nothing is wrong.
+//
+// Errors:
+//
+//    - typednil-error --
+func find(n int) *Error { // want find:"ErrorCodes: typednil-error"
+	if n == 0 {
+		return nil
+	}
+	return &Error{"typednil-error"}
+}
+
+// create never returns nil.
+//
+// Errors:
+//
+//    - typednil-error --
+func create() *Error { // want create:"ErrorCodes: typednil-error"
+	return &Error{"typednil-error"}
+}
+
+// Call returns the result of find, which may be nil.
+//
+// Errors:
+//
+//    - typednil-error --
+func Call(n int) error { // want Call:"ErrorCodes: typednil-error"
+	if n == 1 {
+		return create()
+	}
+	return find(n) // want `find may return a nil \*Error, which is returned as a non-nil error`
+}
+
+// Variable returns a variable, which is nil unless assigned.
+//
+// Errors:
+//
+//    - typednil-error --
+func Variable(n int) error { // want Variable:"ErrorCodes: typednil-error"
+	var err *Error
+	if n == 0 {
+		err = &Error{"typednil-error"}
+	}
+	return err // want `variable "err" of type \*Error may be nil, which is returned as a non-nil error`
+}
+
+// Guarded checks the variables before returning them.
+//
+// Errors:
+//
+//    - typednil-error --
+func Guarded(n int) error { // want Guarded:"ErrorCodes: typednil-error"
+	err := find(n)
+	if err != nil && n > 0 {
+		return err
+	}
+
+	other := err
+	if other == nil {
+		return nil
+	}
+	return other
+}
+
+// Propagated returns a nil variable through another variable.
+//
+// Errors:
+//
+//    - typednil-error --
+func Propagated(n int) error { // want Propagated:"ErrorCodes: typednil-error"
+	err := find(n)
+	other := err
+	return other // want `variable "other" of type \*Error may be nil, which is returned as a non-nil error`
+}
+
+// Reassigned assigns the variable again before returning it.
+//
+// Errors:
+//
+//    - typednil-error --
+func Reassigned() error { // want Reassigned:"ErrorCodes: typednil-error"
+	var err *Error
+	err = &Error{"typednil-error"}
+	return err
+}
+
+// Defaulted assigns the variable, if it is nil.
+//
+// Errors:
+//
+//    - typednil-error --
+func Defaulted(n int) error { // want Defaulted:"ErrorCodes: typednil-error"
+	err := find(n)
+	if err == nil {
+		err = &Error{"typednil-error"}
+	}
+	other := err
+	return other
+}
+
+// Reset assigns nil after the error was created.
+//
+// Errors:
+//
+//    - typednil-error --
+func Reset(n int) error { // want Reset:"ErrorCodes: typednil-error"
+	err := create()
+	if n == 0 {
+		err = nil
+	}
+	return err // want `variable "err" of type \*Error may be nil, which is returned as a non-nil error`
+}
+
+// values returns a nil error type pointer from a function literal.
+func values() (int, error) {
+	fn := func() (int, error) {
+		var err *Error
+		return 0, err // want `variable "err" of type \*Error may be nil, which is returned as a non-nil error`
+	}
+	return fn()
+}
+
+// notCodedNil is not reported, because NotCoded is not an error type with a code.
+func notCodedNil() error {
+	var err *NotCoded
+	return err
+}
+
+// Concrete returns a concrete error type, so nil is fine.
+//
+// Errors:
+//
+//    - typednil-error --
+func Concrete(n int) *Error { // want Concrete:"ErrorCodes: typednil-error"
+	var err *Error
+	if n == 0 {
+		err = &Error{"typednil-error"}
+	}
+	return err
+}
@@ -0,0 +1,17 @@
+package dep
+
+// Fetch fetches something.
+//
+// Errors:
+//
+//    - typos-dep-unavailable --
+func Fetch() error { // want Fetch:"ErrorCodes: typos-dep-unavailable"
+	return &Error{"typos-dep-unavailable"}
+}
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"code", Position:0}, Codes:}`
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
@@ -0,0 +1,40 @@
+package registry
+
+// Declared declares a code, that is not registered.
+//
+// Errors:
+//
+//    - typos-not-fuond -- if nothing was found // want `error code "typos-not-fuond" is not registered, did you mean "typos-not-found"\?`
+func Declared() error { // want Declared:"ErrorCodes: typos-not-fuond"
+	return &Error{"typos-not-fuond"} // want `error code "typos-not-fuond" is not registered, did you mean "typos-not-found"\?`
+}
+
+// Created creates an error with a misspelled code, the declaration is correct.
+//
+// Errors:
+//
+//    - typos-permission-denied --
+func Created() error { // want Created:"ErrorCodes: typos-permission-denied" `function "Created" has a mismatch of declared and actual error codes: missing codes: \[typos-permision-denied\] unused codes: \[typos-permission-denied\] \(did you mean "typos-permission-denied" instead of "typos-permision-denied"\?\)`
+	return &Error{"typos-permision-denied"} // want `error code "typos-permision-denied" is not registered, did you mean "typos-permission-denied"\?`
+}
+
+// Unknown creates an error with a code, that is not similar to any registered code.
+//
+// Errors:
+//
+//    - typos-unknown -- // want `error code "typos-unknown" is not registered$`
+func Unknown() error { // want Unknown:"ErrorCodes: typos-unknown"
+	return &Error{"typos-unknown"} // want `error code "typos-unknown" is not registered$`
+}
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"TheCode", Position:0}, Codes:}`
+	TheCode string
+}
+
+func (e *Error) Code() string  { return e.TheCode }
+func (e *Error) Error() string { return e.TheCode }
+
+type TimeoutError struct{} // want TimeoutError:`ErrorType{Field:<nil>, Codes:typos-timeuot}`
+
+func (e *TimeoutError) Code() string  { return "typos-timeuot" } // want `error code "typos-timeuot" is not registered, did you mean "typos-timeout"\?`
+func (e *TimeoutError) Error() string { return "timeout" }
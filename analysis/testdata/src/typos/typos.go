@@ -0,0 +1,66 @@
+package typos
+
+import "typos/dep"
+
+// Misspelled declares a misspelled code.
+//
+// Errors:
+//
+//    - typos-not-fuond -- if nothing was found
+//    - typos-timeout   --
+func Misspelled(i int) error { // want Misspelled:"ErrorCodes: typos-not-fuond typos-timeout" `function "Misspelled" has a mismatch of declared and actual error codes: missing codes: \[typos-not-found\] unused codes: \[typos-not-fuond\] \(did you mean "typos-not-found" instead of "typos-not-fuond"\?\)`
+	if i == 0 {
+		return &Error{"typos-timeout"}
+	}
+	return &Error{"typos-not-found"}
+}
+
+// MisspelledTwice declares two misspelled codes.
+//
+// Errors:
+//
+//    - typos-permision-denied --
+//    - typos-timeuot          --
+func MisspelledTwice(i int) error { // want MisspelledTwice:"ErrorCodes: typos-permision-denied typos-timeuot" `\(did you mean "typos-permission-denied" instead of "typos-permision-denied", "typos-timeout" instead of "typos-timeuot"\?\)`
+	if i == 0 {
+		return &Error{"typos-timeout"}
+	}
+	return &Error{"typos-permission-denied"}
+}
+
+// Unrelated declares a wrong code, which is not similar to the actual code.
+//
+// Errors:
+//
+//    - typos-timeout --
+func Unrelated() error { // want Unrelated:"ErrorCodes: typos-timeout" `function "Unrelated" has a mismatch of declared and actual error codes: missing codes: \[typos-not-found\] unused codes: \[typos-timeout\]$`
+	return &Error{"typos-not-found"}
+}
+
+// retry declares a misspelled internal code.
+//
+// Errors:
+//
+//    - (internal) typos-retyr --
+func retry() error { // want retry:"ErrorCodes: \\(internal\\) typos-retyr" `\(did you mean "typos-retry" instead of "typos-retyr"\?\)`
+	return &Error{"typos-retry"}
+}
+
+// Unavailable creates a misspelled code of a dependency, and declares the code correctly.
+//
+// Errors:
+//
+//    - typos-dep-unavailable --
+func Unavailable(i int) error { // want Unavailable:"ErrorCodes: typos-dep-unavailable" `missing codes: \[typos-dep-unavialable\] \(did you mean "typos-dep-unavailable" instead of "typos-dep-unavialable"\?\)`
+	if i == 0 {
+		return dep.Fetch()
+	}
+	return &Error{"typos-dep-unavialable"}
+}
+
+type Error struct { // want Error:`ErrorType{Field:{Name:"TheCode", Position:0}, Codes:}`
+	TheCode string
+}
+
+func (e *Error) Code() string  { return e.TheCode }
+func (e *Error) Error() string { return e.TheCode }
@@ -0,0 +1,381 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// findTypedNilReturns emits a diagnostic for each return statement of an error returning function,
+// that returns a pointer to an error type, which may be nil, through an error interface result.
+// A nil pointer converted to an interface is not nil, so callers would see a non-nil error.
+//
+// The following values are reported:
+//   - variables that may be nil when the return statement is reached (see findNilAssignments),
+//     unless they are checked to be non-nil by an enclosing if statement, or a preceding if statement returns if they are nil.
+//   - results of calls to functions of the current package, that return nil for their error type result.
+//
+// Only pointers to types with an ErrorType fact are checked.
+func findTypedNilReturns(c *context) {
+	nilable := findNilAssignments(c)
+	for _, file := range c.pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			var funcType *ast.FuncType
+			var body *ast.BlockStmt
+			switch node := node.(type) {
+			case *ast.FuncDecl:
+				funcType, body = node.Type, node.Body
+			case *ast.FuncLit:
+				funcType, body = node.Type, node.Body
+			default:
+				return true
+			}
+
+			if body == nil || !returnsErrorInterface(c.pass.TypesInfo, funcType) {
+				return true
+			}
+			forEachReturnStmt(body, func(returnStmt *ast.ReturnStmt) {
+				if len(returnStmt.Results) == 0 {
+					return
+				}
+				checkTypedNilReturn(c, nilable, file, returnStmt, returnStmt.Results[len(returnStmt.Results)-1])
+			})
+			return true
+		})
+	}
+}
+
+// returnsErrorInterface returns true, if the last result of the given function type is an interface implementing error.
+func returnsErrorInterface(info *types.Info, funcType *ast.FuncType) bool {
+	if funcType.Results == nil || len(funcType.Results.List) == 0 {
+		return false
+	}
+
+	typ := info.TypeOf(funcType.Results.List[len(funcType.Results.List)-1].Type)
+	return types.IsInterface(typ) && types.Implements(typ, tError)
+}
+
+// forEachReturnStmt calls the given function for each return statement of the given function body,
+// excluding the return statements of nested function literals.
+func forEachReturnStmt(body *ast.BlockStmt, fn func(*ast.ReturnStmt)) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			fn(node)
+		}
+		return true
+	})
+}
+
+func checkTypedNilReturn(c *context, nilable nilAssignments, file *ast.File, returnStmt *ast.ReturnStmt, result ast.Expr) {
+	pass := c.pass
+	typ := pass.TypesInfo.TypeOf(result)
+	if !isErrorTypePointer(c, typ) {
+		return
+	}
+
+	switch expr := astutil.Unparen(result).(type) {
+	case *ast.Ident:
+		variable, ok := pass.TypesInfo.Uses[expr].(*types.Var)
+		if !ok || !nilable.mayBeNil(c, file, variable, returnStmt, map[*types.Var]bool{}) {
+			return
+		}
+		if isCheckedNotNil(c, file, returnStmt, variable) {
+			return
+		}
+		reportRange(pass, categoryTypedNil, expr, "variable %q of type %s may be nil, which is returned as a non-nil error",
+			expr.Name, types.TypeString(typ, types.RelativeTo(pass.Pkg)))
+	case *ast.CallExpr:
+		callee, ok := typeutil.Callee(pass.TypesInfo, expr).(*types.Func)
+		if !ok || !returnsNil(c, callee) {
+			return
+		}
+		reportRange(pass, categoryTypedNil, expr, "%s may return a nil %s, which is returned as a non-nil error",
+			calleeName(pass, callee), types.TypeString(typ, types.RelativeTo(pass.Pkg)))
+	}
+}
+
+type (
+	// nilAssignments maps local variables to their assignments, that may assign nil.
+	nilAssignments map[*types.Var][]nilAssignment
+
+	// nilAssignment is an assignment of nil to a variable. The assigned value is either nil itself,
+	// or another variable, which may be nil at the assignment.
+	nilAssignment struct {
+		node   ast.Node   // assignment statement or value spec
+		source *types.Var // assigned variable, or nil if nil is assigned directly
+	}
+)
+
+// findNilAssignments finds the assignments of the local variables of the current package, that may assign nil.
+//
+// A variable may be assigned nil by its declaration without a value, or by an assignment of
+// the nil literal, the result of a function returning nil (see returnsNil) or another variable that may be nil.
+// Parameters, receivers and global variables are not included, as their values are not known.
+func findNilAssignments(c *context) nilAssignments {
+	info := c.pass.TypesInfo
+	result := nilAssignments{}
+
+	localVar := func(expr ast.Expr) *types.Var {
+		ident, ok := astutil.Unparen(expr).(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		variable, ok := info.ObjectOf(ident).(*types.Var)
+		if !ok || variable.Parent() == nil || variable.Parent() == c.pass.Pkg.Scope() {
+			return nil
+		}
+		return variable
+	}
+	assign := func(node ast.Node, lhs ast.Expr, rhs ast.Expr) {
+		variable := localVar(lhs)
+		if variable == nil {
+			return
+		}
+
+		switch {
+		case rhs == nil || isNilLiteral(info, rhs):
+			result[variable] = append(result[variable], nilAssignment{node, nil})
+		case localVar(rhs) != nil:
+			result[variable] = append(result[variable], nilAssignment{node, localVar(rhs)})
+		default:
+			if callExpr, ok := astutil.Unparen(rhs).(*ast.CallExpr); ok {
+				if callee, ok := typeutil.Callee(info, callExpr).(*types.Func); ok && returnsNil(c, callee) {
+					result[variable] = append(result[variable], nilAssignment{node, nil})
+				}
+			}
+		}
+	}
+	assignAll := func(node ast.Node, lhs []ast.Expr, rhs []ast.Expr) {
+		switch {
+		case len(rhs) == 0: // var x *T
+			for _, expr := range lhs {
+				assign(node, expr, nil)
+			}
+		case len(rhs) == 1 && len(lhs) > 1: // x, err := f()
+			if _, ok := astutil.Unparen(rhs[0]).(*ast.CallExpr); ok {
+				assign(node, lhs[len(lhs)-1], rhs[0])
+			}
+		case len(lhs) == len(rhs):
+			for i := range lhs {
+				assign(node, lhs[i], rhs[i])
+			}
+		}
+	}
+
+	for _, file := range c.pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.AssignStmt:
+				if node.Tok == token.ASSIGN || node.Tok == token.DEFINE {
+					assignAll(node, node.Lhs, node.Rhs)
+				}
+			case *ast.ValueSpec:
+				lhs := make([]ast.Expr, len(node.Names))
+				for i, name := range node.Names {
+					lhs[i] = name
+				}
+				assignAll(node, lhs, node.Values)
+			}
+			return true
+		})
+	}
+	return result
+}
+
+// mayBeNil returns true, if the given variable may be nil when the given node is reached.
+//
+// This is the case, if an assignment of nil precedes the node, and the variable is not assigned again
+// in between in the same or an enclosing block of the node (see isReassignedBefore).
+// Variables that are assigned from other variables may be nil, if the other variable may be nil at the assignment.
+func (nilable nilAssignments) mayBeNil(c *context, file *ast.File, variable *types.Var, node ast.Node, visited map[*types.Var]bool) bool {
+	if visited[variable] {
+		return false
+	}
+	visited[variable] = true
+	defer delete(visited, variable)
+
+	for _, assignment := range nilable[variable] {
+		if assignment.node.Pos() >= node.Pos() || isReassignedBefore(c, file, variable, assignment.node, node) {
+			continue
+		}
+		if assignment.source == nil || nilable.mayBeNil(c, file, assignment.source, assignment.node, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// isReassignedBefore returns true, if the given variable is assigned again after the given assignment,
+// by a statement of the same or an enclosing block of the given node, that precedes the node.
+//
+// An if statement checking "v == nil", which assigns the variable in its body, counts as an assignment as well:
+// the variable is assigned a new value, if it was nil.
+func isReassignedBefore(c *context, file *ast.File, variable *types.Var, assignment ast.Node, node ast.Node) bool {
+	path, _ := astutil.PathEnclosingInterval(file, node.Pos(), node.End())
+	for i := 1; i < len(path); i++ {
+		var stmts []ast.Stmt
+		switch parent := path[i].(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return false
+		case *ast.BlockStmt:
+			stmts = parent.List
+		case *ast.CaseClause:
+			stmts = parent.Body
+		case *ast.CommClause:
+			stmts = parent.Body
+		}
+
+		for _, stmt := range stmts {
+			if stmt == path[i-1] {
+				break
+			}
+			if stmt.Pos() > assignment.End() && assigns(c, stmt, variable) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// assigns returns true, if the given statement assigns the given variable,
+// or is an if statement checking "v == nil" and assigning the variable in its body.
+func assigns(c *context, stmt ast.Stmt, variable *types.Var) bool {
+	switch stmt := stmt.(type) {
+	case *ast.AssignStmt:
+		if stmt.Tok != token.ASSIGN && stmt.Tok != token.DEFINE {
+			return false
+		}
+		for _, lhs := range stmt.Lhs {
+			if ident, ok := astutil.Unparen(lhs).(*ast.Ident); ok && c.pass.TypesInfo.ObjectOf(ident) == variable {
+				return true
+			}
+		}
+	case *ast.IfStmt:
+		if !conditionImplies(c, stmt.Cond, variable, token.EQL, token.LOR) {
+			return false
+		}
+		for _, bodyStmt := range stmt.Body.List {
+			if _, ok := bodyStmt.(*ast.AssignStmt); ok && assigns(c, bodyStmt, variable) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isErrorTypePointer returns true, if the given type is a pointer to a type with an ErrorType fact.
+func isErrorTypePointer(c *context, typ types.Type) bool {
+	pointer, ok := typ.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := pointer.Elem().(*types.Named)
+	if !ok {
+		return false
+	}
+	return c.pass.ImportObjectFact(named.Obj(), new(ErrorType))
+}
+
+// returnsNil returns true, if the given function belongs to the current package,
+// and returns the nil literal as its last result in any of its return statements.
+func returnsNil(c *context, fn *types.Func) bool {
+	if fn.Pkg() != c.pass.Pkg {
+		return false
+	}
+
+	result := false
+	c.lookup.forEach(func(funcDecl *ast.FuncDecl) {
+		if funcDecl.Name.Pos() != fn.Pos() || funcDecl.Body == nil {
+			return
+		}
+		forEachReturnStmt(funcDecl.Body, func(returnStmt *ast.ReturnStmt) {
+			if len(returnStmt.Results) > 0 && isNilLiteral(c.pass.TypesInfo, returnStmt.Results[len(returnStmt.Results)-1]) {
+				result = true
+			}
+		})
+	})
+	return result
+}
+
+// isCheckedNotNil returns true, if the given return statement is only reached when the given variable is not nil:
+// either an enclosing if statement checks "v != nil", or a preceding if statement in an enclosing block checks "v == nil" and returns.
+func isCheckedNotNil(c *context, file *ast.File, returnStmt *ast.ReturnStmt, variable *types.Var) bool {
+	path, _ := astutil.PathEnclosingInterval(file, returnStmt.Pos(), returnStmt.End())
+	for i := 1; i < len(path); i++ {
+		child := path[i-1]
+		switch node := path[i].(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			if child == node.Body && conditionImplies(c, node.Cond, variable, token.NEQ, token.LAND) {
+				return true
+			}
+			if child == node.Else && conditionImplies(c, node.Cond, variable, token.EQL, token.LOR) {
+				return true
+			}
+		case *ast.BlockStmt:
+			if precededByNilReturn(c, node.List, child, variable) {
+				return true
+			}
+		case *ast.CaseClause:
+			if precededByNilReturn(c, node.Body, child, variable) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// precededByNilReturn returns true, if any statement before the given child of the statement list
+// is an if statement checking "v == nil" and ending with a return.
+func precededByNilReturn(c *context, stmts []ast.Stmt, child ast.Node, variable *types.Var) bool {
+	for _, stmt := range stmts {
+		if stmt == child {
+			return false
+		}
+
+		ifStmt, ok := stmt.(*ast.IfStmt)
+		if !ok || len(ifStmt.Body.List) == 0 {
+			continue
+		}
+		if _, ok := ifStmt.Body.List[len(ifStmt.Body.List)-1].(*ast.ReturnStmt); ok && conditionImplies(c, ifStmt.Cond, variable, token.EQL, token.LOR) {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionImplies returns true, if the given condition compares the given variable to nil with the given operator,
+// either directly or as an operand of a chain of the given logical operator.
+// E.g. "v != nil && x" implies "v != nil" for the logical operator "&&".
+func conditionImplies(c *context, cond ast.Expr, variable *types.Var, op token.Token, logicalOp token.Token) bool {
+	binary, ok := astutil.Unparen(cond).(*ast.BinaryExpr)
+	if !ok {
+		return false
+	}
+
+	switch binary.Op {
+	case logicalOp:
+		return conditionImplies(c, binary.X, variable, op, logicalOp) || conditionImplies(c, binary.Y, variable, op, logicalOp)
+	case op:
+		info := c.pass.TypesInfo
+		isVariable := func(expr ast.Expr) bool {
+			ident, ok := astutil.Unparen(expr).(*ast.Ident)
+			return ok && info.Uses[ident] == variable
+		}
+		return isVariable(binary.X) && isNilLiteral(info, binary.Y) || isNilLiteral(info, binary.X) && isVariable(binary.Y)
+	}
+	return false
+}
+
+// isNilLiteral returns true, if the given expression is the untyped nil.
+func isNilLiteral(info *types.Info, expr ast.Expr) bool {
+	basicType, ok := info.TypeOf(expr).(*types.Basic)
+	return ok && basicType.Kind() == types.UntypedNil
+}
@@ -0,0 +1,410 @@
+// Package catalog builds a catalog of all error codes declared in a set of packages.
+//
+// The catalog lists every function and interface method with declared error codes,
+// every error constructor and every error type, together with the descriptions
+// written next to the error codes in the docstrings.
+package catalog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+)
+
+// Kinds of catalog entries.
+const (
+	KindFunction        = "function"
+	KindMethod          = "method"
+	KindInterfaceMethod = "interface-method"
+	KindErrorType       = "error-type"
+)
+
+// Catalog contains the error codes of a set of packages.
+type Catalog struct {
+	Packages []*Package `json:"packages"`
+}
+
+// Package contains the error codes of a single package.
+type Package struct {
+	Path       string       `json:"path"`
+	Functions  []*Function  `json:"functions,omitempty"`
+	ErrorTypes []*ErrorType `json:"errorTypes,omitempty"`
+	Interfaces []*Interface `json:"interfaces,omitempty"`
+}
+
+// Function is a function, method or interface method with declared error codes.
+type Function struct {
+	// Name is "Func" for functions, and "Type.Method" or "(*Type).Method" for methods.
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+
+	// Exported is true if the function is part of the public API of the package:
+	// its name and the name of its receiver type (if any) are exported.
+	Exported bool `json:"exported"`
+
+	// Codes are the error codes the function may return. It is empty for functions declaring "Errors: none".
+	Codes []*Code `json:"codes"`
+
+	// Constructor is set if the function is an error constructor.
+	Constructor *Constructor `json:"constructor,omitempty"`
+
+	// Inferred is set if the function does not declare error codes, and the codes were inferred by the analyzer.
+	Inferred bool `json:"inferred,omitempty"`
+}
+
+// Code is a single error code, with the description found in the docstring (if any).
+type Code struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+
+	// Origins are the called functions and the created error types the returned errors with the code may originate from,
+	// sorted by package and name. They are resolved by the analyzer from the return statements of the function,
+	// following calls of functions without declared error codes. It is empty if the origin of the code is unknown,
+	// e.g. if the code is assigned to the error code field of an existing error.
+	Origins []*Origin `json:"origins,omitempty"`
+}
+
+// Origin identifies a called function or a created error type, that an error code originates from.
+type Origin struct {
+	Package string `json:"package"`
+	Name    string `json:"name"`
+
+	// Kind is the kind of the called function, or KindErrorType.
+	Kind string `json:"kind"`
+}
+
+// Constructor describes the error code parameter of an error constructor.
+type Constructor struct {
+	Param       string `json:"param"`
+	Position    int    `json:"position"`
+	Description string `json:"description,omitempty"`
+}
+
+// ErrorType is a type implementing the error interface, with a legible Code() method.
+type ErrorType struct {
+	Name     string `json:"name"`
+	Exported bool   `json:"exported"`
+
+	// Codes are the constant error codes returned by the Code() method, if any.
+	Codes []string `json:"codes,omitempty"`
+
+	// Field is the name of the field returned by the Code() method, if any.
+	Field string `json:"field,omitempty"`
+}
+
+// Interface is an interface with methods declaring error codes.
+// The codes of the methods are listed as functions of kind KindInterfaceMethod.
+type Interface struct {
+	Name     string `json:"name"`
+	Exported bool   `json:"exported"`
+
+	// Methods are the names of the methods declaring error codes, sorted by name.
+	Methods []string `json:"methods"`
+}
+
+// New creates a catalog from the result of running analysis.Analyzer with the driver.
+// Only the packages matched by the patterns given to the driver are included.
+func New(result *driver.Result) *Catalog {
+	catalog := &Catalog{Packages: []*Package{}}
+	for _, pkg := range result.Packages {
+		catalog.Packages = append(catalog.Packages, newPackage(result, pkg))
+	}
+	return catalog
+}
+
+func newPackage(result *driver.Result, pkg *driver.Package) *Package {
+	docs := collectDocs(pkg.Syntax)
+	catalogPkg := &Package{Path: pkg.PkgPath}
+
+	for _, objectFact := range result.ObjectFacts(pkg.Types) {
+		switch fact := objectFact.Fact.(type) {
+		case *analysis.ErrorCodes:
+			fn, ok := objectFact.Object.(*types.Func)
+			if !ok {
+				continue
+			}
+			catalogPkg.Functions = append(catalogPkg.Functions, newFunction(result, pkg, fn, fact, docs[fn.Pos()]))
+		case *analysis.ErrorType:
+			catalogPkg.ErrorTypes = append(catalogPkg.ErrorTypes, newErrorType(objectFact.Object, fact))
+		case *analysis.ErrorInterface:
+			catalogPkg.Interfaces = append(catalogPkg.Interfaces, newInterface(objectFact.Object, fact))
+		}
+	}
+
+	sort.Slice(catalogPkg.Functions, func(i, j int) bool {
+		return catalogPkg.Functions[i].Name < catalogPkg.Functions[j].Name
+	})
+	sort.Slice(catalogPkg.ErrorTypes, func(i, j int) bool {
+		return catalogPkg.ErrorTypes[i].Name < catalogPkg.ErrorTypes[j].Name
+	})
+	sort.Slice(catalogPkg.Interfaces, func(i, j int) bool {
+		return catalogPkg.Interfaces[i].Name < catalogPkg.Interfaces[j].Name
+	})
+	return catalogPkg
+}
+
+func newFunction(result *driver.Result, pkg *driver.Package, fn *types.Func, fact *analysis.ErrorCodes, doc *ast.CommentGroup) *Function {
+	name, kind, receiverExported := functionName(fn)
+	function := &Function{
+		Name:     name,
+		Kind:     kind,
+		Exported: fn.Exported() && receiverExported,
+		Codes:    []*Code{},
+		Inferred: fact.Inferred,
+	}
+
+	// The docstring was already validated by the analyzer, so errors are not expected here.
+	errorDocs := &analysis.ErrorDocs{}
+	if doc != nil {
+		if parsed, err := analysis.ParseErrorDocs(doc.Text()); err == nil && parsed != nil {
+			errorDocs = parsed
+		}
+	}
+
+	origins := findOrigins(pkg, fn, fact.Codes)
+	codes := fact.Codes.Slice()
+	sort.Strings(codes)
+	for _, code := range codes {
+		function.Codes = append(function.Codes, &Code{code, errorDocs.Descriptions[code], origins[code]})
+	}
+
+	var constructor analysis.ErrorConstructor
+	if result.ObjectFact(fn, &constructor) {
+		params := fn.Type().(*types.Signature).Params()
+		function.Constructor = &Constructor{
+			Param:       params.At(constructor.CodeParamPosition).Name(),
+			Position:    constructor.CodeParamPosition,
+			Description: errorDocs.ParamDescription,
+		}
+	}
+
+	return function
+}
+
+func newErrorType(obj types.Object, fact *analysis.ErrorType) *ErrorType {
+	errorType := &ErrorType{
+		Name:     obj.Name(),
+		Exported: obj.Exported(),
+		Codes:    append([]string(nil), fact.Codes...),
+	}
+	sort.Strings(errorType.Codes)
+	if fact.Field != nil {
+		errorType.Field = fact.Field.Name
+	}
+	return errorType
+}
+
+// findOrigins returns the origins of each of the given codes returned by the given function,
+// using the sources of the returned errors resolved by the analysis of the package.
+// Calls whose errors are not returned are not origins.
+func findOrigins(pkg *driver.Package, fn *types.Func, codes analysis.CodeSet) map[string][]*Origin {
+	analysisResult, ok := pkg.Result.(*analysis.Result)
+	if !ok {
+		return nil
+	}
+	sources, _ := analysisResult.CodeSources(fn)
+
+	origins := map[string][]*Origin{}
+	for _, source := range sources {
+		origin := &Origin{Package: source.Object.Pkg().Path(), Name: source.Object.Name(), Kind: KindErrorType}
+		if callee, ok := source.Object.(*types.Func); ok {
+			origin.Name, origin.Kind, _ = functionName(callee)
+		}
+		for code := range source.Codes {
+			if _, ok := codes[code]; ok {
+				origins[code] = append(origins[code], origin)
+			}
+		}
+	}
+
+	for _, codeOrigins := range origins {
+		sort.Slice(codeOrigins, func(i, j int) bool {
+			if codeOrigins[i].Package != codeOrigins[j].Package {
+				return codeOrigins[i].Package < codeOrigins[j].Package
+			}
+			return codeOrigins[i].Name < codeOrigins[j].Name
+		})
+	}
+	return origins
+}
+
+func newInterface(obj types.Object, fact *analysis.ErrorInterface) *Interface {
+	result := &Interface{Name: obj.Name(), Exported: obj.Exported(), Methods: []string{}}
+	for name := range fact.ErrorMethods {
+		result.Methods = append(result.Methods, name)
+	}
+	sort.Strings(result.Methods)
+	return result
+}
+
+// FunctionName returns the name of the given function as used in the catalog, e.g. "Take", "Store.Get" or "(*Store).Get",
+// and whether it is exported. Methods are only exported, if their receiver type is exported too.
+func FunctionName(fn *types.Func) (string, bool) {
+	name, _, receiverExported := functionName(fn)
+	return name, fn.Exported() && receiverExported
+}
+
+// functionName returns the name of the given function as "Func", "Type.Method" or "(*Type).Method",
+// together with the kind of the function and whether the receiver type (if any) is exported.
+func functionName(fn *types.Func) (string, string, bool) {
+	signature := fn.Type().(*types.Signature)
+	if signature.Recv() == nil {
+		return fn.Name(), KindFunction, true
+	}
+
+	receiver := signature.Recv().Type()
+	pointer, isPointer := receiver.(*types.Pointer)
+	if isPointer {
+		receiver = pointer.Elem()
+	}
+
+	named, ok := receiver.(*types.Named)
+	if !ok {
+		return fn.Name(), KindMethod, false
+	}
+
+	kind := KindMethod
+	if types.IsInterface(named) {
+		kind = KindInterfaceMethod
+	}
+
+	if isPointer {
+		return fmt.Sprintf("(*%s).%s", named.Obj().Name(), fn.Name()), kind, named.Obj().Exported()
+	}
+	return fmt.Sprintf("%s.%s", named.Obj().Name(), fn.Name()), kind, named.Obj().Exported()
+}
+
+// collectDocs maps the position of each function name and interface method name
+// in the given files to its doc comment.
+func collectDocs(files []*ast.File) map[token.Pos]*ast.CommentGroup {
+	docs := map[token.Pos]*ast.CommentGroup{}
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FuncDecl:
+				docs[node.Name.Pos()] = node.Doc
+				return false
+			case *ast.InterfaceType:
+				for _, method := range node.Methods.List {
+					for _, name := range method.Names {
+						docs[name.Pos()] = method.Doc
+					}
+				}
+			}
+			return true
+		})
+	}
+	return docs
+}
+
+// Read reads a catalog in the JSON format written by WriteJSON.
+func Read(r io.Reader) (*Catalog, error) {
+	catalog := &Catalog{}
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(catalog); err != nil {
+		return nil, fmt.Errorf("invalid catalog: %v", err)
+	}
+	return catalog, nil
+}
+
+// WriteJSON writes the catalog as indented JSON to the given writer.
+func (catalog *Catalog) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(catalog)
+}
+
+// csvHeader contains the column names of the CSV format.
+var csvHeader = []string{"package", "name", "kind", "exported", "code", "description"}
+
+// WriteCSV writes the catalog as CSV to the given writer, with one row per error code.
+//
+// Error constructors get an additional row with kind "error-constructor" and the code "param:<name>".
+// Error types get one row per constant code, or a single row with the code "field:<name>".
+// Functions declaring "Errors: none" get a single row with an empty code.
+func (catalog *Catalog) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, pkg := range catalog.Packages {
+		for _, fn := range pkg.Functions {
+			exported := strconv.FormatBool(fn.Exported)
+			if len(fn.Codes) == 0 {
+				if err := writer.Write([]string{pkg.Path, fn.Name, fn.Kind, exported, "", ""}); err != nil {
+					return err
+				}
+			}
+			for _, code := range fn.Codes {
+				if err := writer.Write([]string{pkg.Path, fn.Name, fn.Kind, exported, code.Code, code.Description}); err != nil {
+					return err
+				}
+			}
+			if fn.Constructor != nil {
+				row := []string{pkg.Path, fn.Name, "error-constructor", exported, "param:" + fn.Constructor.Param, fn.Constructor.Description}
+				if err := writer.Write(row); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, errorType := range pkg.ErrorTypes {
+			exported := strconv.FormatBool(errorType.Exported)
+			for _, code := range errorType.Codes {
+				if err := writer.Write([]string{pkg.Path, errorType.Name, "error-type", exported, code, ""}); err != nil {
+					return err
+				}
+			}
+			if errorType.Field != "" {
+				if err := writer.Write([]string{pkg.Path, errorType.Name, "error-type", exported, "field:" + errorType.Field, ""}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Codes returns all error codes declared by functions or returned by error types of the catalog.
+func (catalog *Catalog) Codes() analysis.CodeSet {
+	result := analysis.Set()
+	for _, pkg := range catalog.Packages {
+		for _, fn := range pkg.Functions {
+			for _, code := range fn.Codes {
+				result.Add(code.Code)
+			}
+		}
+		for _, errorType := range pkg.ErrorTypes {
+			for _, code := range errorType.Codes {
+				result.Add(code)
+			}
+		}
+	}
+	return result
+}
+
+// UnusedRegisteredCodes returns the codes of the given registry, which are never returned in the catalog.
+// The codes are returned in the order of the registry.
+func (catalog *Catalog) UnusedRegisteredCodes(registry *analysis.Registry) []analysis.RegisteredCode {
+	used := catalog.Codes()
+	var result []analysis.RegisteredCode
+	for _, entry := range registry.Codes {
+		if _, ok := used[entry.Code]; !ok {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
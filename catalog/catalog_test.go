@@ -0,0 +1,153 @@
+package catalog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+	"golang.org/x/tools/go/packages"
+)
+
+// loadTestdata runs the analyzer on the given packages in testdata/src using GOPATH mode.
+func loadTestdata(t *testing.T, patterns ...string) *driver.Result {
+	t.Helper()
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &packages.Config{
+		Dir: testdata,
+		Env: append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off", "GOPROXY=off"),
+	}
+	result, err := driver.Run(analysis.Analyzer, config, patterns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pkg := range result.Packages {
+		for _, diagnostic := range pkg.Diagnostics {
+			t.Errorf("unexpected diagnostic in %s: %s", pkg.PkgPath, diagnostic.Message)
+		}
+	}
+	return result
+}
+
+func TestCatalog(t *testing.T) {
+	catalog := New(loadTestdata(t, "shop", "shop/inventory"))
+
+	expected := &Catalog{Packages: []*Package{
+		{
+			Path: "shop",
+			Functions: []*Function{
+				{Name: "Buy", Kind: KindFunction, Exported: true, Codes: []*Code{
+					{"inventory-insufficient", "if the item is sold out", []*Origin{{"shop/inventory", "Take", KindFunction}}},
+					{"inventory-not-found", "if the item is not sold in the shop", []*Origin{{"shop/inventory", "Take", KindFunction}}},
+				}},
+				{Name: "Restock", Kind: KindFunction, Exported: true, Codes: []*Code{
+					{"inventory-insufficient", "if the item cannot be restocked", []*Origin{{"shop/inventory", "NewError", KindFunction}}},
+					{"inventory-not-found", "if the item is not sold in the shop", []*Origin{{"shop/inventory", "ErrNotFound", KindErrorType}}},
+				}},
+				{Name: "memoryStore.Get", Kind: KindMethod, Exported: false, Codes: []*Code{
+					{"inventory-not-found", "if the item does not exist", []*Origin{{"shop/inventory", "ErrNotFound", KindErrorType}}},
+				}},
+			},
+		},
+		{
+			Path: "shop/inventory",
+			Functions: []*Function{
+				{Name: "Check", Kind: KindFunction, Exported: true, Codes: []*Code{}},
+				{Name: "NewError", Kind: KindFunction, Exported: true, Codes: []*Code{},
+					Constructor: &Constructor{"code", 0, "the code of the created error"}},
+				{Name: "Store.Get", Kind: KindInterfaceMethod, Exported: true, Codes: []*Code{
+					{"inventory-not-found", "if the item does not exist", nil},
+				}},
+				{Name: "Take", Kind: KindFunction, Exported: true, Codes: []*Code{
+					{"inventory-insufficient", "if there are not enough items left", []*Origin{{"shop/inventory", "NewError", KindFunction}}},
+					{"inventory-not-found", "if the item does not exist", []*Origin{{"shop/inventory", "Store.Get", KindInterfaceMethod}}},
+				}},
+			},
+			ErrorTypes: []*ErrorType{
+				{Name: "ErrNotFound", Exported: true, Codes: []string{"inventory-not-found"}},
+				{Name: "Error", Exported: true, Codes: []string{}, Field: "code"},
+			},
+			Interfaces: []*Interface{
+				{Name: "Store", Exported: true, Methods: []string{"Get"}},
+			},
+		},
+	}}
+
+	var actual, want bytes.Buffer
+	if err := catalog.WriteJSON(&actual); err != nil {
+		t.Fatal(err)
+	}
+	if err := expected.WriteJSON(&want); err != nil {
+		t.Fatal(err)
+	}
+	if actual.String() != want.String() {
+		t.Errorf("expected catalog:\n%s\nbut got:\n%s", want.String(), actual.String())
+	}
+
+	read, err := Read(strings.NewReader(actual.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written bytes.Buffer
+	if err := read.WriteJSON(&written); err != nil {
+		t.Fatal(err)
+	}
+	if written.String() != actual.String() {
+		t.Errorf("catalog changed after reading it back:\n%s", written.String())
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	catalog := &Catalog{Packages: []*Package{{
+		Path: "example.org/pkg",
+		Functions: []*Function{
+			{Name: "Check", Kind: KindFunction, Exported: true, Codes: []*Code{}},
+			{Name: "newError", Kind: KindFunction, Codes: []*Code{{"pkg-unknown", "if the code is empty", nil}},
+				Constructor: &Constructor{"code", 0, "the code, may contain a comma, or two"}},
+		},
+		ErrorTypes: []*ErrorType{
+			{Name: "Error", Exported: true, Field: "code"},
+			{Name: "ErrNotFound", Exported: true, Codes: []string{"pkg-not-found"}},
+		},
+	}}}
+
+	var buffer bytes.Buffer
+	if err := catalog.WriteCSV(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"package,name,kind,exported,code,description",
+		"example.org/pkg,Check,function,true,,",
+		"example.org/pkg,newError,function,false,pkg-unknown,if the code is empty",
+		`example.org/pkg,newError,error-constructor,false,param:code,"the code, may contain a comma, or two"`,
+		"example.org/pkg,Error,error-type,true,field:code,",
+		"example.org/pkg,ErrNotFound,error-type,true,pkg-not-found,",
+		"",
+	}, "\n")
+	if buffer.String() != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, buffer.String())
+	}
+}
+
+func TestUnusedRegisteredCodes(t *testing.T) {
+	catalog := New(loadTestdata(t, "shop/inventory"))
+	registry := &analysis.Registry{Codes: []analysis.RegisteredCode{
+		{Code: "inventory-not-found"},
+		{Code: "inventory-legacy", Owner: "team-inventory"},
+		{Code: "inventory-insufficient"},
+	}}
+
+	unused := catalog.UnusedRegisteredCodes(registry)
+	if len(unused) != 1 || unused[0].Code != "inventory-legacy" {
+		t.Errorf("expected only inventory-legacy to be unused, but got %v", unused)
+	}
+}
@@ -0,0 +1,59 @@
+package inventory
+
+// Error is the error type of the inventory.
+type Error struct {
+	code    string
+	message string
+}
+
+func (e *Error) Error() string { return e.message }
+func (e *Error) Code() string  { return e.code }
+
+// ErrNotFound is returned when an item does not exist.
+type ErrNotFound struct{}
+
+func (ErrNotFound) Error() string { return "not found" }
+func (ErrNotFound) Code() string  { return "inventory-not-found" }
+
+// NewError creates a new inventory error.
+//
+// Errors:
+//
+//    - param: code -- the code of the created error
+func NewError(code, message string) error {
+	return &Error{code, message}
+}
+
+// Store gives access to the items.
+type Store interface {
+	// Get returns the amount of the given item.
+	//
+	// Errors:
+	//
+	//    - inventory-not-found -- if the item does not exist
+	Get(item string) (int, error)
+}
+
+// Take removes the given amount of the item.
+//
+// Errors:
+//
+//    - inventory-not-found    -- if the item does not exist
+//    - inventory-insufficient -- if there are not enough items left
+func Take(store Store, item string, amount int) error {
+	available, err := store.Get(item)
+	if err != nil {
+		return err
+	}
+	if available < amount {
+		return NewError("inventory-insufficient", "not enough items")
+	}
+	return nil
+}
+
+// Check never fails.
+//
+// Errors: none -- the error is only returned for compatibility.
+func Check() error {
+	return nil
+}
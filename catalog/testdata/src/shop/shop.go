@@ -0,0 +1,41 @@
+package shop
+
+import "shop/inventory"
+
+type memoryStore map[string]int
+
+// Get returns the amount of the given item.
+//
+// Errors:
+//
+//    - inventory-not-found -- if the item does not exist
+func (store memoryStore) Get(item string) (int, error) {
+	amount, ok := store[item]
+	if !ok {
+		return 0, inventory.ErrNotFound{}
+	}
+	return amount, nil
+}
+
+// Buy buys a single item.
+//
+// Errors:
+//
+//    - inventory-not-found    -- if the item is not sold in the shop
+//    - inventory-insufficient -- if the item is sold out
+func Buy(item string) error {
+	return inventory.Take(memoryStore{}, item, 1)
+}
+
+// Restock makes an item available again.
+//
+// Errors:
+//
+//    - inventory-not-found    -- if the item is not sold in the shop
+//    - inventory-insufficient -- if the item cannot be restocked
+func Restock(item string) error {
+	if err := inventory.Take(memoryStore{}, item, 0); err != nil {
+		return inventory.ErrNotFound{}
+	}
+	return inventory.NewError("inventory-insufficient", "cannot be restocked")
+}
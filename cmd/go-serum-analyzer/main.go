@@ -1,9 +1,116 @@
 // The analyse command runs the error code analyzer.
+//
+// With -format=sarif the diagnostics are written to stdout as a SARIF log for code scanning platforms,
+// instead of the output of the standard analysis driver:
+//
+//	go-serum-analyzer -format=sarif [flags] [packages] > results.sarif
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+	"github.com/serum-errors/go-serum-analyzer/sarif"
 	"golang.org/x/tools/go/analysis/singlechecker"
 )
 
-func main() { singlechecker.Main(analysis.Analyzer) }
+func main() {
+	format, args := extractFormat(os.Args[1:], &analysis.Analyzer.Flags)
+	switch format {
+	case "", "text":
+		os.Args = append(os.Args[:1], args...)
+		singlechecker.Main(analysis.Analyzer)
+	case "sarif":
+		if err := runSARIF(args); err != nil {
+			fmt.Fprintf(os.Stderr, "go-serum-analyzer: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "go-serum-analyzer: unknown format %q\n", format)
+		os.Exit(2)
+	}
+}
+
+// driverValueFlags are the flags of the standard analysis driver, that take a separate value.
+var driverValueFlags = map[string]bool{"c": true, "debug": true, "cpuprofile": true, "memprofile": true, "trace": true}
+
+// extractFormat removes the -format flag from the given command line arguments,
+// and returns its value together with the remaining arguments.
+// The standard analysis driver does not know the flag, so it has to be removed before the driver parses the arguments.
+//
+// The given flags are the flags of the analyzer: the values of its non-boolean flags may be given as separate arguments,
+// e.g. "-config file", so they are skipped instead of ending the flags.
+func extractFormat(args []string, flags *flag.FlagSet) (string, []string) {
+	var format string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		switch {
+		case strings.HasPrefix(name, "format="):
+			format = strings.TrimPrefix(name, "format=")
+		case name == "format" && i+1 < len(args):
+			format = args[i+1]
+			i++
+		case takesValue(flags, name) && i+1 < len(args):
+			rest = append(rest, arg, args[i+1])
+			i++
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return format, rest
+}
+
+// takesValue checks if the flag with the given name takes a separate value.
+// The name does not take a value, if it already contains one, e.g. "config=file".
+func takesValue(flags *flag.FlagSet, name string) bool {
+	if strings.Contains(name, "=") {
+		return false
+	}
+	if driverValueFlags[name] {
+		return true
+	}
+
+	f := flags.Lookup(name)
+	if f == nil {
+		return false
+	}
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return !ok || !boolFlag.IsBoolFlag()
+}
+
+func runSARIF(args []string) error {
+	flags := flag.NewFlagSet("go-serum-analyzer", flag.ExitOnError)
+	analysis.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		flags.Var(f.Value, f.Name, f.Usage)
+	})
+	flags.Parse(args)
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	result, err := driver.Run(analysis.Analyzer, nil, patterns...)
+	if err != nil {
+		return err
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	tool := sarif.Tool{Name: "go-serum-analyzer", InformationURI: "https://github.com/serum-errors/go-serum-analyzer"}
+	return sarif.New(tool, result, root).Write(os.Stdout)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+)
+
+func TestExtractFormat(t *testing.T) {
+	tests := []struct {
+		args   []string
+		format string
+		rest   []string
+	}{
+		{[]string{"./..."}, "", []string{"./..."}},
+		{[]string{"-format=sarif", "./..."}, "sarif", []string{"./..."}},
+		{[]string{"-format", "sarif", "./..."}, "sarif", []string{"./..."}},
+		{[]string{"-config", "x", "-format=sarif", "./..."}, "sarif", []string{"-config", "x", "./..."}},
+		{[]string{"--baseline", "b.json", "-strict", "-format", "text", "./..."}, "text", []string{"--baseline", "b.json", "-strict", "./..."}},
+		{[]string{"-config=x", "-strict", "./...", "-format=sarif"}, "", []string{"-config=x", "-strict", "./...", "-format=sarif"}},
+		{[]string{"-c", "2", "-format=sarif", "./..."}, "sarif", []string{"-c", "2", "./..."}},
+		{[]string{"-format=sarif", "--", "-format"}, "sarif", []string{"--", "-format"}},
+	}
+
+	for _, test := range tests {
+		format, rest := extractFormat(test.args, &analysis.Analyzer.Flags)
+		if format != test.format || !reflect.DeepEqual(rest, test.rest) {
+			t.Errorf("extractFormat(%q) = %q, %q, expected %q, %q", test.args, format, rest, test.format, test.rest)
+		}
+	}
+}
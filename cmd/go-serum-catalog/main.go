@@ -0,0 +1,63 @@
+// The go-serum-catalog command writes a catalog of all error codes declared in a set of packages.
+//
+// Usage:
+//
+//	go-serum-catalog [-format=json|csv] [-o file] [packages]
+//
+// If no packages are given, "./..." is used.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+)
+
+func main() {
+	format := flag.String("format", "json", "output format: json or csv")
+	output := flag.String("o", "", "write the catalog to the given file instead of stdout")
+	flag.Parse()
+
+	if err := run(*format, *output, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "go-serum-catalog: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(format, output string, patterns []string) error {
+	var write func(*catalog.Catalog, io.Writer) error
+	switch format {
+	case "json":
+		write = (*catalog.Catalog).WriteJSON
+	case "csv":
+		write = (*catalog.Catalog).WriteCSV
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	result, err := driver.Run(analysis.Analyzer, nil, patterns...)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	return write(catalog.New(result), w)
+}
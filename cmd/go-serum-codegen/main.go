@@ -0,0 +1,58 @@
+// The go-serum-codegen command generates the error codes, error type and error constructors of a package from a registry file.
+//
+// Usage:
+//
+//	go-serum-codegen -spec file [-prefix prefix] [-package name] [-type name] [-o file]
+//
+// The registry file has the same format as the registry of the analyzer (see analysis.Registry).
+// Only codes starting with the prefix are generated, and the prefix is trimmed from the names of the constants.
+//
+// The command is meant to be run by go generate:
+//
+//	//go:generate go-serum-codegen -spec ../codes.yaml -prefix storage-
+//
+// If -package is not given, the package name is taken from $GOPACKAGE, which is set by go generate.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/codegen"
+)
+
+func main() {
+	spec := flag.String("spec", "", "registry file listing the error codes")
+	prefix := flag.String("prefix", "", "only generate codes with this prefix, and trim it from the names of the constants")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name of the generated file")
+	typeName := flag.String("type", "Error", "name of the generated error type")
+	output := flag.String("o", "errors_gen.go", "file the code is written to")
+	flag.Parse()
+
+	if *spec == "" || flag.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "usage: go-serum-codegen -spec file [-prefix prefix] [-package name] [-type name] [-o file]")
+		os.Exit(2)
+	}
+
+	if err := run(*spec, *output, codegen.Options{Package: *pkg, Type: *typeName, Prefix: *prefix, Source: filepath.Base(*spec)}); err != nil {
+		fmt.Fprintf(os.Stderr, "go-serum-codegen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(spec, output string, options codegen.Options) error {
+	registry, err := analysis.ReadRegistry(spec)
+	if err != nil {
+		return err
+	}
+
+	source, err := codegen.Generate(registry, options)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(output, source, 0o644)
+}
@@ -0,0 +1,96 @@
+// The go-serum-compat command compares the error codes of the exported API of a module against a previous version.
+//
+// Usage:
+//
+//	go-serum-compat -old=<catalog.json|dir> [-new=<catalog.json|dir>] [-format=markdown|json] [-fail-on-breaking] [packages]
+//
+// Each version is either a catalog written by go-serum-catalog, or a directory containing the module,
+// e.g. a previous git revision checked out with "git worktree add".
+// Directories are analysed using the given package patterns, "./..." by default.
+// The new version defaults to the current directory.
+//
+// With -fail-on-breaking the command exits with status 1 if a breaking change was found.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+	"github.com/serum-errors/go-serum-analyzer/compat"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	oldVersion := flag.String("old", "", "catalog file or module directory of the previous version (required)")
+	newVersion := flag.String("new", ".", "catalog file or module directory of the new version")
+	format := flag.String("format", "markdown", "output format: markdown or json")
+	failOnBreaking := flag.Bool("fail-on-breaking", false, "exit with status 1 if a breaking change was found")
+	flag.Parse()
+
+	report, err := run(*oldVersion, *newVersion, *format, flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-serum-compat: %v\n", err)
+		os.Exit(2)
+	}
+
+	if *failOnBreaking && report.Breaking() {
+		os.Exit(1)
+	}
+}
+
+func run(oldVersion, newVersion, format string, patterns []string) (*compat.Report, error) {
+	if oldVersion == "" {
+		return nil, fmt.Errorf("the -old flag is required")
+	}
+	if format != "markdown" && format != "json" {
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	oldCatalog, err := loadCatalog(oldVersion, patterns)
+	if err != nil {
+		return nil, fmt.Errorf("could not load old version: %v", err)
+	}
+	newCatalog, err := loadCatalog(newVersion, patterns)
+	if err != nil {
+		return nil, fmt.Errorf("could not load new version: %v", err)
+	}
+
+	report := compat.Compare(oldCatalog, newCatalog)
+	if format == "json" {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteMarkdown(os.Stdout)
+	}
+	return report, err
+}
+
+// loadCatalog reads the catalog file at the given path,
+// or creates a catalog by analysing the packages in the given directory.
+func loadCatalog(path string, patterns []string) (*catalog.Catalog, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return catalog.Read(file)
+	}
+
+	result, err := driver.Run(analysis.Analyzer, &packages.Config{Dir: path}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.New(result), nil
+}
@@ -0,0 +1,105 @@
+// The go-serum-coverage command reports how many error returning functions of a set of packages document their error codes.
+//
+// Usage:
+//
+//	go-serum-coverage [-format=text|json] [-exported] [-worst=n] [-min=percent] [packages]
+//	go-serum-coverage -tests [-format=text|json] [packages]
+//
+// If no packages are given, "./..." is used.
+//
+// With -min the command exits with status 1 if the total coverage is below the given percentage.
+//
+// With -tests the packages are loaded with their tests, and the command lists the declared error codes,
+// that no test expects with serumtest.ExpectCode. It exits with status 1 if there are any.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/coverage"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	format := flag.String("format", "text", "output format: text or json")
+	exportedOnly := flag.Bool("exported", false, "only count exported functions and methods of exported types")
+	worst := flag.Int("worst", 10, "number of packages with the most undocumented functions to list in text output")
+	minimum := flag.Float64("min", 0, "exit with status 1 if the total coverage in percent is below this value")
+	tests := flag.Bool("tests", false, "list the declared error codes, that are not expected by any test")
+	flag.Parse()
+
+	if *tests {
+		untested, err := runTests(*format, flag.Args())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "go-serum-coverage: %v\n", err)
+			os.Exit(2)
+		}
+		if len(untested) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	report, err := run(*format, *exportedOnly, *worst, flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-serum-coverage: %v\n", err)
+		os.Exit(2)
+	}
+
+	if percent := report.Total.Percent(); percent < *minimum {
+		fmt.Fprintf(os.Stderr, "go-serum-coverage: coverage %.1f%% is below the minimum of %.1f%%\n", percent, *minimum)
+		os.Exit(1)
+	}
+}
+
+func run(format string, exportedOnly bool, worst int, patterns []string) (*coverage.Report, error) {
+	if format != "text" && format != "json" {
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	result, err := driver.Run(analysis.Analyzer, nil, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	report := coverage.New(result, exportedOnly)
+	if format == "json" {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout, worst)
+	}
+	return report, err
+}
+
+func runTests(format string, patterns []string) ([]coverage.UntestedCode, error) {
+	if format != "text" && format != "json" {
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	result, err := driver.Run(analysis.Analyzer, &packages.Config{Tests: true}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	untested := coverage.Untested(result)
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return untested, encoder.Encode(append([]coverage.UntestedCode{}, untested...))
+	}
+	for _, code := range untested {
+		fmt.Printf("%s.%s: error code %q is not expected by any test\n", code.Package, code.Function, code.Code)
+	}
+	return untested, nil
+}
@@ -0,0 +1,85 @@
+// The go-serum-flowgraph command writes a graph of how error codes flow through a set of packages.
+//
+// Usage:
+//
+//	go-serum-flowgraph [-format=dot|graphml] [-code=patterns] [-package=patterns] [-o file] [packages]
+//
+// The graph can be restricted to the codes matching a comma-separated list of glob patterns (e.g. "db-error-*"),
+// and to the flows into or out of packages matching a comma-separated list of package patterns (e.g. "example.org/api/...").
+// If no packages are given, "./..." is used.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+	"github.com/serum-errors/go-serum-analyzer/flowgraph"
+)
+
+func main() {
+	format := flag.String("format", "dot", "output format: dot or graphml")
+	codes := flag.String("code", "", "comma-separated glob patterns of the codes to include")
+	packages := flag.String("package", "", "comma-separated patterns of the packages to include")
+	output := flag.String("o", "", "write the graph to the given file instead of stdout")
+	flag.Parse()
+
+	filter := flowgraph.Filter{Codes: splitList(*codes), Packages: splitList(*packages)}
+	if err := run(*format, filter, *output, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "go-serum-flowgraph: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(format string, filter flowgraph.Filter, output string, patterns []string) error {
+	var write func(*flowgraph.Graph, io.Writer) error
+	switch format {
+	case "dot":
+		write = (*flowgraph.Graph).WriteDOT
+	case "graphml":
+		write = (*flowgraph.Graph).WriteGraphML
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	result, err := driver.Run(analysis.Analyzer, nil, patterns...)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	return write(flowgraph.New(catalog.New(result), filter), w)
+}
+
+// splitList splits a comma-separated list, ignoring empty entries.
+func splitList(list string) []string {
+	var result []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
@@ -0,0 +1,25 @@
+// The go-serum-lint command runs the error code analyzer together with companion analyzers for error handling.
+//
+// Usage:
+//
+//	go-serum-lint [-serum.strict] [-serum.config=file] [-errorsas] [-nilness] [packages]
+//
+// The flags of the error code analyzer are prefixed with "serum.".
+// By default all analyzers are run; naming analyzers as flags runs only those.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+	"golang.org/x/tools/go/analysis/passes/errorsas"
+	"golang.org/x/tools/go/analysis/passes/nilness"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+)
+
+func main() {
+	multichecker.Main(
+		analysis.Analyzer,
+		errorsas.Analyzer,
+		nilness.Analyzer,
+	)
+}
@@ -0,0 +1,48 @@
+// The go-serum-reference command generates error reference pages for a set of packages.
+//
+// Usage:
+//
+//	go-serum-reference [-format=markdown|html] [-o dir] [packages]
+//
+// One page is written per package, together with an index page.
+// If no packages are given, "./..." is used.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+	"github.com/serum-errors/go-serum-analyzer/reference"
+)
+
+func main() {
+	format := flag.String("format", reference.FormatMarkdown, "output format: markdown or html")
+	output := flag.String("o", "errors", "directory the pages are written to")
+	flag.Parse()
+
+	if err := run(*format, *output, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "go-serum-reference: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(format, output string, patterns []string) error {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	result, err := driver.Run(analysis.Analyzer, nil, patterns...)
+	if err != nil {
+		return err
+	}
+
+	files, err := reference.Generate(catalog.New(result), format)
+	if err != nil {
+		return err
+	}
+	return reference.Write(output, files)
+}
@@ -0,0 +1,73 @@
+// The go-serum-registry command reports registered error codes that are never returned by the given packages.
+//
+// Usage:
+//
+//	go-serum-registry [-registry file] [packages]
+//
+// If no registry is given, the registry of the configuration file found in the current directory is used.
+// If no packages are given, "./..." is used.
+// The command exits with status 1 if unused codes were found.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+)
+
+func main() {
+	registryFile := flag.String("registry", "", "path of the error code registry")
+	flag.Parse()
+
+	unused, err := run(*registryFile, flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go-serum-registry: %v\n", err)
+		os.Exit(2)
+	}
+
+	for _, entry := range unused {
+		if entry.Owner != "" {
+			fmt.Printf("registered error code %q (owner: %s) is never returned\n", entry.Code, entry.Owner)
+		} else {
+			fmt.Printf("registered error code %q is never returned\n", entry.Code)
+		}
+	}
+	if len(unused) > 0 {
+		os.Exit(1)
+	}
+}
+
+func run(registryFile string, patterns []string) ([]analysis.RegisteredCode, error) {
+	if registryFile == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		registryFile, err = analysis.FindRegistryFile(dir)
+		if err != nil {
+			return nil, err
+		}
+		if registryFile == "" {
+			return nil, fmt.Errorf("no registry configured, use the -registry flag")
+		}
+	}
+
+	registry, err := analysis.ReadRegistry(registryFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	result, err := driver.Run(analysis.Analyzer, nil, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.New(result).UnusedRegisteredCodes(registry), nil
+}
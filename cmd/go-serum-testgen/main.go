@@ -0,0 +1,73 @@
+// The go-serum-testgen command generates a table-driven test skeleton for the error codes declared by a function.
+//
+// Usage:
+//
+//	go-serum-testgen [-o file] package function
+//
+// The function is named like in the catalog, e.g. "Open", "Store.Get" or "(*Store).Get".
+// The test is written to the test file belonging to the file declaring the function, unless -o is given.
+//
+// Existing tests are never overwritten: if the test function already exists in the file,
+// only cases for the codes missing in its table are appended.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+	"github.com/serum-errors/go-serum-analyzer/testgen"
+)
+
+func main() {
+	output := flag.String("o", "", "file the test is written to (default: the test file of the function's file)")
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: go-serum-testgen [-o file] package function")
+		os.Exit(2)
+	}
+
+	if err := run(*output, flag.Arg(0), flag.Arg(1)); err != nil {
+		fmt.Fprintf(os.Stderr, "go-serum-testgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(output, pattern, name string) error {
+	result, err := driver.Run(analysis.Analyzer, nil, pattern)
+	if err != nil {
+		return err
+	}
+
+	fn, err := testgen.Find(result, name)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = fn.TestFile()
+	}
+
+	existing, err := ioutil.ReadFile(output)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	source, added, err := testgen.Generate(existing, fn)
+	if err != nil {
+		return fmt.Errorf("%s: %v", output, err)
+	}
+	if len(added) == 0 {
+		fmt.Printf("%s: %s already covers all error codes of %s\n", output, fn.TestName(), fn.Name)
+		return nil
+	}
+
+	if err := ioutil.WriteFile(output, source, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("%s: added %d test cases to %s: %v\n", output, len(added), fn.TestName(), added)
+	return nil
+}
@@ -0,0 +1,191 @@
+// Package codegen generates the error code boilerplate of a package from a registry file:
+// constants for the error codes, an error type with a Code() method, error constructors and a lookup of the code descriptions.
+//
+// The generated code follows the conventions of the analyzer, so it passes the analysis without diagnostics,
+// and the constructors can be used with constant codes in functions declaring error codes.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+)
+
+// Options configure the generated code.
+type Options struct {
+	// Package is the name of the package of the generated file.
+	Package string
+	// Type is the name of the generated error type. If empty, "Error" is used.
+	Type string
+	// Prefix selects the codes to generate: only codes starting with the prefix are included.
+	// The prefix is trimmed from the codes to build the names of the constants.
+	Prefix string
+	// Source is the name of the registry file, mentioned in the header of the generated file.
+	Source string
+}
+
+type (
+	file struct {
+		Options
+		Codes []*code
+	}
+
+	code struct {
+		Name        string
+		Code        string
+		Description string
+		Deprecated  string // deprecation notice, if the code is deprecated
+	}
+)
+
+// Generate generates the Go source of a file containing the registered codes selected by the options.
+func Generate(registry *analysis.Registry, options Options) ([]byte, error) {
+	if options.Type == "" {
+		options.Type = "Error"
+	}
+	if !token.IsIdentifier(options.Package) || !token.IsIdentifier(options.Type) {
+		return nil, fmt.Errorf("invalid package name %q or type name %q", options.Package, options.Type)
+	}
+
+	names := map[string]string{}
+	data := &file{Options: options}
+	for _, registered := range registry.Codes {
+		if !strings.HasPrefix(registered.Code, options.Prefix) {
+			continue
+		}
+
+		name := constantName(strings.TrimPrefix(registered.Code, options.Prefix))
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("codes %q and %q both result in the constant %s", other, registered.Code, name)
+		}
+		names[name] = registered.Code
+		data.Codes = append(data.Codes, &code{name, registered.Code, registered.Description, deprecation(registered, options.Prefix)})
+	}
+	if len(data.Codes) == 0 {
+		return nil, fmt.Errorf("no registered codes start with the prefix %q", options.Prefix)
+	}
+
+	sort.Slice(data.Codes, func(i, j int) bool {
+		return data.Codes[i].Name < data.Codes[j].Name
+	})
+
+	var buffer bytes.Buffer
+	if err := fileTemplate.Execute(&buffer, data); err != nil {
+		return nil, err
+	}
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("could not format generated code: %v", err)
+	}
+	return source, nil
+}
+
+// deprecation returns the deprecation notice of the given code, or an empty string if the code is not deprecated.
+func deprecation(registered analysis.RegisteredCode, prefix string) string {
+	switch {
+	case registered.Stability != analysis.StabilityDeprecated:
+		return ""
+	case registered.Replacement == "":
+		return "Deprecated: the code should not be returned anymore."
+	case strings.HasPrefix(registered.Replacement, prefix):
+		return fmt.Sprintf("Deprecated: use %s instead.", constantName(strings.TrimPrefix(registered.Replacement, prefix)))
+	default:
+		return fmt.Sprintf("Deprecated: use the code %q instead.", registered.Replacement)
+	}
+}
+
+// constantName returns the name of the constant of the given code, e.g. "CodeNotFound" for "not-found".
+func constantName(code string) string {
+	var name strings.Builder
+	name.WriteString("Code")
+	for _, part := range strings.Split(code, "-") {
+		if part != "" {
+			name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return name.String()
+}
+
+var fileTemplate = template.Must(template.New("").Funcs(template.FuncMap{"comment": comment}).Parse(`// Code generated by go-serum-codegen{{ if .Source }} from {{ .Source }}{{ end }}. DO NOT EDIT.
+
+package {{ .Package }}
+
+import "fmt"
+
+// Error codes of the package.
+const (
+{{- range .Codes }}
+	{{- if .Description }}
+	// {{ .Name }}: {{ comment .Description }}
+	{{- end }}
+	{{- if .Deprecated }}
+	{{- if .Description }}
+	//
+	{{- end }}
+	// {{ .Deprecated }}
+	{{- end }}
+	{{ .Name }} = {{ printf "%q" .Code }}
+{{- end }}
+)
+
+// {{ .Type }} is an error with one of the error codes of the package.
+type {{ .Type }} struct {
+	code    string
+	message string
+}
+
+// Code returns the error code of the error.
+func (e *{{ .Type }}) Code() string {
+	return e.code
+}
+
+// Error returns the error code, followed by the message of the error.
+func (e *{{ .Type }}) Error() string {
+	if e.message == "" {
+		return e.code
+	}
+	return e.code + ": " + e.message
+}
+
+// New{{ .Type }} creates an error with the given code and message.
+//
+// Errors:
+//
+//   - param: code -- the code of the created error
+func New{{ .Type }}(code string, message string) *{{ .Type }} {
+	return &{{ .Type }}{code, message}
+}
+
+// New{{ .Type }}f creates an error with the given code and a message formatted like fmt.Sprintf.
+//
+// Errors:
+//
+//   - param: code -- the code of the created error
+func New{{ .Type }}f(code string, format string, args ...interface{}) *{{ .Type }} {
+	return &{{ .Type }}{code, fmt.Sprintf(format, args...)}
+}
+
+var descriptions = map[string]string{
+{{- range .Codes }}
+	{{ .Name }}: {{ printf "%q" .Description }},
+{{- end }}
+}
+
+// Describe returns the description of the given error code of the package.
+// The second result is false, if the code is not an error code of the package.
+func Describe(code string) (string, bool) {
+	description, ok := descriptions[code]
+	return description, ok
+}
+`))
+
+// comment returns the given text with line breaks continued as line comments.
+func comment(text string) string {
+	return strings.ReplaceAll(strings.TrimSpace(text), "\n", "\n\t// ")
+}
@@ -0,0 +1,125 @@
+package codegen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+	"golang.org/x/tools/go/packages"
+)
+
+func readTestRegistry(t *testing.T) *analysis.Registry {
+	t.Helper()
+	registry, err := analysis.ReadRegistry(filepath.Join("testdata", "codes.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return registry
+}
+
+func TestGenerate(t *testing.T) {
+	source, err := Generate(readTestRegistry(t), Options{Package: "storage", Prefix: "storage-", Source: "codes.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"// Code generated by go-serum-codegen from codes.yaml. DO NOT EDIT.\n\npackage storage\n",
+		"// CodeDenied: the caller may not access the item,\n\t// or the storage is read-only\n\tCodeDenied = \"storage-denied\"\n",
+		"// Deprecated: use CodeNotFound instead.\n\tCodeGone = \"storage-gone\"\n",
+		"func (e *Error) Code() string {\n\treturn e.code\n}",
+		"func NewErrorf(code string, format string, args ...interface{}) *Error {",
+		"\tCodeNotFound: \"the requested item does not exist\",\n",
+	}
+	for _, part := range expected {
+		if !strings.Contains(string(source), part) {
+			t.Errorf("generated code should contain %q, but was:\n%s", part, source)
+		}
+	}
+	if strings.Contains(string(source), "other-error") {
+		t.Errorf("generated code should only contain codes with the prefix, but was:\n%s", source)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	registry := readTestRegistry(t)
+	tests := []struct {
+		options Options
+		err     string
+	}{
+		{Options{Package: "storage", Prefix: "unknown-"}, `no registered codes start with the prefix "unknown-"`},
+		{Options{Package: "my-package"}, `invalid package name "my-package"`},
+		{Options{Package: "storage", Type: "a.b"}, `type name "a.b"`},
+	}
+
+	for _, test := range tests {
+		if _, err := Generate(registry, test.options); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("Generate(%+v) should return error containing %q but returned: %v", test.options, test.err, err)
+		}
+	}
+
+	registry = &analysis.Registry{Codes: []analysis.RegisteredCode{{Code: "a-b"}, {Code: "a--b"}}}
+	if _, err := Generate(registry, Options{Package: "a"}); err == nil || !strings.Contains(err.Error(), "both result in the constant CodeAB") {
+		t.Errorf("Generate should report colliding constant names, but returned: %v", err)
+	}
+}
+
+// TestGeneratedCodeAnalysis runs the analyzer on the generated code and a file using the generated constructors.
+func TestGeneratedCodeAnalysis(t *testing.T) {
+	source, err := Generate(readTestRegistry(t), Options{Package: "storage", Prefix: "storage-"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gopath, err := ioutil.TempDir("", "serum-codegen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(gopath)
+
+	dir := filepath.Join(gopath, "src", "storage")
+	use, err := ioutil.ReadFile(filepath.Join("testdata", "use.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "errors_gen.go"), source, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "use.go"), use, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &packages.Config{
+		Dir: dir,
+		Env: append(os.Environ(), "GOPATH="+gopath, "GO111MODULE=off", "GOPROXY=off"),
+	}
+	result, err := driver.Run(analysis.Analyzer, config, "storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg := result.Packages[0]
+	for _, diagnostic := range pkg.Diagnostics {
+		t.Errorf("unexpected diagnostic at %v: %s", pkg.Fset.Position(diagnostic.Pos), diagnostic.Message)
+	}
+
+	var constructor analysis.ErrorConstructor
+	if !result.ObjectFact(pkg.Types.Scope().Lookup("NewErrorf"), &constructor) || constructor.CodeParamPosition != 0 {
+		t.Errorf("NewErrorf should be an error constructor")
+	}
+	var codes analysis.ErrorCodes
+	if !result.ObjectFact(pkg.Types.Scope().Lookup("Get"), &codes) || codes.String() != "ErrorCodes: storage-denied storage-not-found" {
+		t.Errorf("Get should declare the codes of the constructor calls, but has %v", &codes)
+	}
+	var errorType analysis.ErrorType
+	if !result.ObjectFact(pkg.Types.Scope().Lookup("Error"), &errorType) || errorType.Field == nil || errorType.Field.Name != "code" {
+		t.Errorf("Error should be an error type with the code field, but is %v", &errorType)
+	}
+}
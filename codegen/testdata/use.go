@@ -0,0 +1,14 @@
+package storage
+
+// Get gets an item.
+//
+// Errors:
+//
+//    - storage-not-found -- if there is no item with the given name
+//    - storage-denied -- if the item is private
+func Get(name string) (string, error) {
+	if name == "" {
+		return "", NewError(CodeNotFound, "no name")
+	}
+	return "", NewErrorf(CodeDenied, "item %q is private", name)
+}
@@ -0,0 +1,289 @@
+// Package compat compares the error code surface of the exported API between two versions of a module.
+//
+// Both versions are described by a catalog (see package catalog).
+// Only exported functions, methods, interface methods and interfaces are compared.
+package compat
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+)
+
+// Kinds of changes.
+const (
+	// CodeAdded means a function may return a code, it did not return before.
+	// Callers may need to handle the new code, so the change is breaking.
+	CodeAdded = "code-added"
+
+	// CodeRemoved means a function no longer returns a code.
+	// This is breaking for interface methods, because existing implementations may still return the code.
+	CodeRemoved = "code-removed"
+
+	// CodeMoved means a code was removed from one function and added to another function of the same kind and package.
+	// The change is breaking like CodeAdded.
+	CodeMoved = "code-moved"
+
+	// InterfaceMethodAdded means a method of an interface started declaring error codes.
+	// Existing implementations may return other codes, so the change is breaking.
+	InterfaceMethodAdded = "interface-method-added"
+
+	// InterfaceMethodRemoved means a method of an interface no longer declares error codes.
+	InterfaceMethodRemoved = "interface-method-removed"
+)
+
+// Change is a single difference in the error code surface between two versions.
+type Change struct {
+	Kind     string `json:"kind"`
+	Breaking bool   `json:"breaking"`
+	Package  string `json:"package"`
+
+	// Name is the name of the function, or of the interface for interface method changes.
+	Name string `json:"name"`
+
+	// Code is the changed error code, for code changes.
+	Code string `json:"code,omitempty"`
+
+	// From is the name of the function the code was moved from, for CodeMoved.
+	From string `json:"from,omitempty"`
+
+	// Method is the name of the interface method, for interface method changes.
+	Method string `json:"method,omitempty"`
+}
+
+// Report contains all changes between two versions.
+type Report struct {
+	Changes []*Change `json:"changes"`
+}
+
+// Breaking returns true if the report contains a breaking change.
+func (report *Report) Breaking() bool {
+	for _, change := range report.Changes {
+		if change.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare compares the exported API of the old and the new version.
+//
+// Functions, interfaces and packages, that only exist in one of the versions, are not compared:
+// adding or removing them is a change of the API itself, not of its error codes.
+func Compare(oldVersion, newVersion *catalog.Catalog) *Report {
+	report := &Report{Changes: []*Change{}}
+
+	oldPackages := packagesByPath(oldVersion)
+	for _, newPkg := range newVersion.Packages {
+		oldPkg, ok := oldPackages[newPkg.Path]
+		if !ok {
+			continue
+		}
+
+		report.Changes = append(report.Changes, compareFunctions(oldPkg, newPkg)...)
+		report.Changes = append(report.Changes, compareInterfaces(oldPkg, newPkg)...)
+	}
+
+	sort.SliceStable(report.Changes, func(i, j int) bool {
+		a, b := report.Changes[i], report.Changes[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.Code < b.Code
+	})
+	return report
+}
+
+func packagesByPath(c *catalog.Catalog) map[string]*catalog.Package {
+	result := make(map[string]*catalog.Package, len(c.Packages))
+	for _, pkg := range c.Packages {
+		result[pkg.Path] = pkg
+	}
+	return result
+}
+
+// exportedFunctions maps the names of all exported functions of the package to the set of their codes.
+func exportedFunctions(pkg *catalog.Package) (map[string]map[string]struct{}, map[string]string) {
+	codes := map[string]map[string]struct{}{}
+	kinds := map[string]string{}
+	for _, fn := range pkg.Functions {
+		if !fn.Exported {
+			continue
+		}
+		codes[fn.Name] = map[string]struct{}{}
+		kinds[fn.Name] = fn.Kind
+		for _, code := range fn.Codes {
+			codes[fn.Name][code.Code] = struct{}{}
+		}
+	}
+	return codes, kinds
+}
+
+func compareFunctions(oldPkg, newPkg *catalog.Package) []*Change {
+	oldCodes, oldKinds := exportedFunctions(oldPkg)
+	newCodes, kinds := exportedFunctions(newPkg)
+
+	var added, removed []*Change
+	for _, name := range sortedFunctionNames(newCodes) {
+		before, ok := oldCodes[name]
+		if !ok {
+			continue
+		}
+		after := newCodes[name]
+
+		for _, code := range sortedCodes(after) {
+			if _, ok := before[code]; !ok {
+				added = append(added, &Change{Kind: CodeAdded, Breaking: true, Package: newPkg.Path, Name: name, Code: code})
+			}
+		}
+		for _, code := range sortedCodes(before) {
+			if _, ok := after[code]; !ok {
+				breaking := kinds[name] == catalog.KindInterfaceMethod
+				removed = append(removed, &Change{Kind: CodeRemoved, Breaking: breaking, Package: newPkg.Path, Name: name, Code: code})
+			}
+		}
+	}
+
+	// Pair removed and added codes of functions of the same kind to moves.
+	// A move replaces both the addition and the removal. Each removal is used for at most one move.
+	var result []*Change
+	for _, addition := range added {
+		for i, removal := range removed {
+			if removal != nil && removal.Code == addition.Code && removal.Name != addition.Name && oldKinds[removal.Name] == kinds[addition.Name] {
+				addition.Kind, addition.From = CodeMoved, removal.Name
+				removed[i] = nil
+				break
+			}
+		}
+		result = append(result, addition)
+	}
+	for _, removal := range removed {
+		if removal != nil {
+			result = append(result, removal)
+		}
+	}
+	return result
+}
+
+func compareInterfaces(oldPkg, newPkg *catalog.Package) []*Change {
+	oldInterfaces := map[string]*catalog.Interface{}
+	for _, iface := range oldPkg.Interfaces {
+		oldInterfaces[iface.Name] = iface
+	}
+
+	var result []*Change
+	for _, iface := range newPkg.Interfaces {
+		old, ok := oldInterfaces[iface.Name]
+		if !ok || !iface.Exported {
+			continue
+		}
+
+		before, after := stringSet(old.Methods), stringSet(iface.Methods)
+		for _, method := range iface.Methods {
+			if _, ok := before[method]; !ok {
+				result = append(result, &Change{Kind: InterfaceMethodAdded, Breaking: true, Package: newPkg.Path, Name: iface.Name, Method: method})
+			}
+		}
+		for _, method := range old.Methods {
+			if _, ok := after[method]; !ok {
+				result = append(result, &Change{Kind: InterfaceMethodRemoved, Package: newPkg.Path, Name: iface.Name, Method: method})
+			}
+		}
+	}
+	return result
+}
+
+func stringSet(values []string) map[string]struct{} {
+	result := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		result[value] = struct{}{}
+	}
+	return result
+}
+
+func sortedCodes(codes map[string]struct{}) []string {
+	result := make([]string, 0, len(codes))
+	for code := range codes {
+		result = append(result, code)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func sortedFunctionNames(functions map[string]map[string]struct{}) []string {
+	result := make([]string, 0, len(functions))
+	for name := range functions {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Description returns a sentence describing the change, e.g. for release notes.
+func (change *Change) Description() string {
+	name := fmt.Sprintf("`%s.%s`", change.Package, change.Name)
+	switch change.Kind {
+	case CodeAdded:
+		return fmt.Sprintf("%s may now return the error code `%s`", name, change.Code)
+	case CodeRemoved:
+		return fmt.Sprintf("%s no longer returns the error code `%s`", name, change.Code)
+	case CodeMoved:
+		return fmt.Sprintf("the error code `%s` moved from `%s.%s` to %s", change.Code, change.Package, change.From, name)
+	case InterfaceMethodAdded:
+		return fmt.Sprintf("the method `%s` of the interface %s now declares error codes", change.Method, name)
+	case InterfaceMethodRemoved:
+		return fmt.Sprintf("the method `%s` of the interface %s no longer declares error codes", change.Method, name)
+	}
+	return fmt.Sprintf("%s changed (%s)", name, change.Kind)
+}
+
+// WriteMarkdown writes the report as Markdown, suitable for release notes.
+// Breaking changes are listed first.
+func (report *Report) WriteMarkdown(w io.Writer) error {
+	var breaking, other []string
+	for _, change := range report.Changes {
+		line := "* " + change.Description() + "\n"
+		if change.Breaking {
+			breaking = append(breaking, line)
+		} else {
+			other = append(other, line)
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString("# Error Code Changes\n")
+	if len(report.Changes) == 0 {
+		builder.WriteString("\nNo changes.\n")
+	}
+	if len(breaking) > 0 {
+		builder.WriteString("\n## Breaking Changes\n\n")
+		builder.WriteString(strings.Join(breaking, ""))
+	}
+	if len(other) > 0 {
+		builder.WriteString("\n## Other Changes\n\n")
+		builder.WriteString(strings.Join(other, ""))
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// WriteJSON writes the report as indented JSON.
+func (report *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(report)
+}
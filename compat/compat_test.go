@@ -0,0 +1,128 @@
+package compat
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+)
+
+func function(name, kind string, exported bool, codes ...string) *catalog.Function {
+	result := &catalog.Function{Name: name, Kind: kind, Exported: exported, Codes: []*catalog.Code{}}
+	for _, code := range codes {
+		result.Codes = append(result.Codes, &catalog.Code{Code: code})
+	}
+	return result
+}
+
+var (
+	oldCatalog = &catalog.Catalog{Packages: []*catalog.Package{{
+		Path: "example.org/store",
+		Functions: []*catalog.Function{
+			function("Get", catalog.KindFunction, true, "store-not-found"),
+			function("Put", catalog.KindFunction, true, "store-full", "store-invalid"),
+			function("Delete", catalog.KindFunction, true, "store-not-found", "store-locked"),
+			function("helper", catalog.KindFunction, false, "store-full"),
+			function("Store.Get", catalog.KindInterfaceMethod, true, "store-not-found", "store-timeout"),
+			function("Removed", catalog.KindFunction, true, "store-gone"),
+		},
+		Interfaces: []*catalog.Interface{
+			{Name: "Store", Exported: true, Methods: []string{"Get", "List"}},
+		},
+	}}}
+
+	newCatalog = &catalog.Catalog{Packages: []*catalog.Package{
+		{
+			Path: "example.org/store",
+			Functions: []*catalog.Function{
+				function("Get", catalog.KindFunction, true, "store-not-found", "store-timeout"),
+				function("Put", catalog.KindFunction, true, "store-full", "store-locked"),
+				function("Delete", catalog.KindFunction, true, "store-not-found"),
+				function("helper", catalog.KindFunction, false),
+				function("Store.Get", catalog.KindInterfaceMethod, true, "store-not-found"),
+				function("Added", catalog.KindFunction, true, "store-new"),
+			},
+			Interfaces: []*catalog.Interface{
+				{Name: "Store", Exported: true, Methods: []string{"Get", "Put"}},
+			},
+		},
+		{
+			Path:      "example.org/added",
+			Functions: []*catalog.Function{function("New", catalog.KindFunction, true, "added-error")},
+		},
+	}}
+)
+
+func TestCompare(t *testing.T) {
+	report := Compare(oldCatalog, newCatalog)
+
+	expected := []*Change{
+		{Kind: CodeAdded, Breaking: true, Package: "example.org/store", Name: "Get", Code: "store-timeout"},
+		{Kind: CodeMoved, Breaking: true, Package: "example.org/store", Name: "Put", Code: "store-locked", From: "Delete"},
+		{Kind: CodeRemoved, Package: "example.org/store", Name: "Put", Code: "store-invalid"},
+		{Kind: InterfaceMethodAdded, Breaking: true, Package: "example.org/store", Name: "Store", Method: "Put"},
+		{Kind: InterfaceMethodRemoved, Package: "example.org/store", Name: "Store", Method: "List"},
+		{Kind: CodeRemoved, Breaking: true, Package: "example.org/store", Name: "Store.Get", Code: "store-timeout"},
+	}
+
+	if len(report.Changes) != len(expected) {
+		t.Fatalf("expected %d changes, but got %d: %v", len(expected), len(report.Changes), descriptions(report))
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(expected[i], report.Changes[i]) {
+			t.Errorf("change %d: expected %+v but got %+v", i, expected[i], report.Changes[i])
+		}
+	}
+	if !report.Breaking() {
+		t.Errorf("expected the report to contain breaking changes")
+	}
+}
+
+func descriptions(report *Report) []string {
+	var result []string
+	for _, change := range report.Changes {
+		result = append(result, change.Description())
+	}
+	return result
+}
+
+func TestCompareUnchanged(t *testing.T) {
+	report := Compare(oldCatalog, oldCatalog)
+	if len(report.Changes) != 0 || report.Breaking() {
+		t.Errorf("expected no changes, but got %v", descriptions(report))
+	}
+
+	var buffer bytes.Buffer
+	if err := report.WriteMarkdown(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "# Error Code Changes\n\nNo changes.\n"; buffer.String() != expected {
+		t.Errorf("expected %q but got %q", expected, buffer.String())
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	report := &Report{Changes: []*Change{
+		{Kind: CodeAdded, Breaking: true, Package: "example.org/store", Name: "Get", Code: "store-timeout"},
+		{Kind: CodeMoved, Breaking: true, Package: "example.org/store", Name: "Put", Code: "store-locked", From: "Delete"},
+		{Kind: CodeRemoved, Package: "example.org/store", Name: "Delete", Code: "store-locked"},
+		{Kind: InterfaceMethodRemoved, Package: "example.org/store", Name: "Store", Method: "List"},
+	}}
+
+	var buffer bytes.Buffer
+	if err := report.WriteMarkdown(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "# Error Code Changes\n" +
+		"\n## Breaking Changes\n\n" +
+		"* `example.org/store.Get` may now return the error code `store-timeout`\n" +
+		"* the error code `store-locked` moved from `example.org/store.Delete` to `example.org/store.Put`\n" +
+		"\n## Other Changes\n\n" +
+		"* `example.org/store.Delete` no longer returns the error code `store-locked`\n" +
+		"* the method `List` of the interface `example.org/store.Store` no longer declares error codes\n"
+	if buffer.String() != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, buffer.String())
+	}
+}
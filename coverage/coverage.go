@@ -0,0 +1,147 @@
+// Package coverage reports how many error returning functions of a set of packages document their error codes.
+//
+// Error returning functions and interface methods either declare error codes (or an error code parameter),
+// declare "Errors: none", or are undocumented. The report counts these per package,
+// which allows tracking the adoption of error code docs without requiring them everywhere with -strict.
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+)
+
+// Report contains the documentation coverage of a set of packages.
+type Report struct {
+	Packages []*Package `json:"packages"` // sorted by import path
+	Total    Counts     `json:"total"`
+}
+
+// Package contains the documentation coverage of a single package.
+type Package struct {
+	Path   string `json:"path"`
+	Counts Counts `json:"counts"`
+
+	// Undocumented are the names of the undocumented functions, sorted by name.
+	Undocumented []string `json:"undocumented,omitempty"`
+}
+
+// Counts contains the number of error returning functions per documentation state.
+type Counts struct {
+	Codes        int `json:"codes"`
+	None         int `json:"none"`
+	Undocumented int `json:"undocumented"`
+}
+
+// Functions returns the number of all error returning functions.
+func (c Counts) Functions() int {
+	return c.Codes + c.None + c.Undocumented
+}
+
+// Percent returns the percentage of documented functions, or 100 if there are no error returning functions.
+func (c Counts) Percent() float64 {
+	if c.Functions() == 0 {
+		return 100
+	}
+	return float64(c.Codes+c.None) * 100 / float64(c.Functions())
+}
+
+// New creates a report from the result of running analysis.Analyzer with the driver.
+// If exportedOnly is set, only exported functions and methods of exported types are counted.
+func New(result *driver.Result, exportedOnly bool) *Report {
+	report := &Report{Packages: []*Package{}}
+	for _, pkg := range result.Packages {
+		analysisResult, ok := pkg.Result.(*analysis.Result)
+		if !ok {
+			continue
+		}
+
+		coveragePkg := &Package{Path: pkg.PkgPath}
+		for _, doc := range analysisResult.DocCoverage() {
+			name, exported := catalog.FunctionName(doc.Func)
+			if exportedOnly && !exported {
+				continue
+			}
+
+			switch doc.State {
+			case analysis.DocCodes:
+				coveragePkg.Counts.Codes++
+			case analysis.DocNone:
+				coveragePkg.Counts.None++
+			default:
+				coveragePkg.Counts.Undocumented++
+				coveragePkg.Undocumented = append(coveragePkg.Undocumented, name)
+			}
+		}
+		sort.Strings(coveragePkg.Undocumented)
+
+		report.Packages = append(report.Packages, coveragePkg)
+		report.Total.Codes += coveragePkg.Counts.Codes
+		report.Total.None += coveragePkg.Counts.None
+		report.Total.Undocumented += coveragePkg.Counts.Undocumented
+	}
+	return report
+}
+
+// Worst returns up to n packages with the most undocumented functions.
+// Packages without undocumented functions are never included.
+func (report *Report) Worst(n int) []*Package {
+	var result []*Package
+	for _, pkg := range report.Packages {
+		if pkg.Counts.Undocumented > 0 {
+			result = append(result, pkg)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Counts.Undocumented > result[j].Counts.Undocumented
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// WriteText writes the report as a table, followed by the undocumented functions of the worst packages (see Worst).
+func (report *Report) WriteText(w io.Writer, worst int) error {
+	table := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(table, "PACKAGE\tCODES\tNONE\tUNDOCUMENTED\tCOVERAGE")
+	for _, pkg := range report.Packages {
+		writeRow(table, pkg.Path, pkg.Counts)
+	}
+	writeRow(table, "TOTAL", report.Total)
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	worstPackages := report.Worst(worst)
+	if len(worstPackages) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w, "\nWorst offenders:")
+	for _, pkg := range worstPackages {
+		fmt.Fprintf(w, "\n%s (%d undocumented):\n", pkg.Path, pkg.Counts.Undocumented)
+		for _, name := range pkg.Undocumented {
+			fmt.Fprintf(w, "\t%s\n", name)
+		}
+	}
+	return nil
+}
+
+func writeRow(w io.Writer, name string, counts Counts) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1f%%\n", name, counts.Codes, counts.None, counts.Undocumented, counts.Percent())
+}
+
+// WriteJSON writes the report as indented JSON.
+func (report *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,122 @@
+package coverage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+	"golang.org/x/tools/go/packages"
+)
+
+func loadTestdata(t *testing.T, patterns ...string) *driver.Result {
+	t.Helper()
+	return loadTestdataConfig(t, &packages.Config{}, patterns...)
+}
+
+func loadTestdataConfig(t *testing.T, config *packages.Config, patterns ...string) *driver.Result {
+	t.Helper()
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.Dir = testdata
+	config.Env = append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off", "GOPROXY=off")
+	result, err := driver.Run(analysis.Analyzer, config, patterns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestNew(t *testing.T) {
+	result := loadTestdata(t, "docs", "docs/inner")
+
+	expected := &Report{
+		Packages: []*Package{
+			{"docs", Counts{Codes: 2, None: 1, Undocumented: 3}, []string{"Read", "Store.Put", "write"}},
+			{"docs/inner", Counts{None: 1}, nil},
+		},
+		Total: Counts{Codes: 2, None: 2, Undocumented: 3},
+	}
+	if report := New(result, false); !reflect.DeepEqual(report, expected) {
+		t.Errorf("unexpected report:\n%s", writeJSON(t, report))
+	}
+
+	expected = &Report{
+		Packages: []*Package{
+			{"docs", Counts{Codes: 2, None: 1, Undocumented: 2}, []string{"Read", "Store.Put"}},
+			{"docs/inner", Counts{None: 1}, nil},
+		},
+		Total: Counts{Codes: 2, None: 2, Undocumented: 2},
+	}
+	if report := New(result, true); !reflect.DeepEqual(report, expected) {
+		t.Errorf("unexpected report for exported functions:\n%s", writeJSON(t, report))
+	}
+}
+
+func TestCounts(t *testing.T) {
+	if percent := (Counts{}).Percent(); percent != 100 {
+		t.Errorf("coverage without functions should be 100%% but was %v", percent)
+	}
+	if percent := (Counts{Codes: 1, None: 2, Undocumented: 1}).Percent(); percent != 75 {
+		t.Errorf("coverage should be 75%% but was %v", percent)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	report := New(loadTestdata(t, "docs", "docs/inner"), false)
+
+	var buffer bytes.Buffer
+	if err := report.WriteText(&buffer, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `PACKAGE     CODES  NONE  UNDOCUMENTED  COVERAGE
+docs        2      1     3             50.0%
+docs/inner  0      1     0             100.0%
+TOTAL       2      2     3             57.1%
+
+Worst offenders:
+
+docs (3 undocumented):
+	Read
+	Store.Put
+	write
+`
+	if actual := buffer.String(); actual != expected {
+		t.Errorf("unexpected text output:\n%s", strings.ReplaceAll(actual, " ", "."))
+	}
+}
+
+func writeJSON(t *testing.T, report *Report) string {
+	var buffer bytes.Buffer
+	if err := report.WriteJSON(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.String()
+}
+
+func TestUntested(t *testing.T) {
+	result := loadTestdataConfig(t, &packages.Config{Tests: true}, "tested")
+
+	expected := []UntestedCode{{"tested", "Open", "tested-denied"}}
+	if untested := Untested(result); !reflect.DeepEqual(untested, expected) {
+		t.Errorf("Untested should return %v but returned %v", expected, untested)
+	}
+
+	// Without the tests, no code is expected.
+	expected = []UntestedCode{
+		{"tested", "(*Store).Close", "tested-busy"},
+		{"tested", "Open", "tested-denied"},
+		{"tested", "Open", "tested-missing"},
+	}
+	if untested := Untested(loadTestdata(t, "tested")); !reflect.DeepEqual(untested, expected) {
+		t.Errorf("Untested without tests should return %v but returned %v", expected, untested)
+	}
+}
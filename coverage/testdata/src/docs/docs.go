@@ -0,0 +1,45 @@
+package docs
+
+import "errors"
+
+// Open opens something.
+//
+// Errors:
+//
+//    - docs-not-found -- if nothing was found
+func Open() error {
+	return &Error{"docs-not-found"}
+}
+
+// Close closes something.
+//
+// Errors: none
+func Close() error {
+	return nil
+}
+
+func Read() error {
+	return errors.New("unknown")
+}
+
+func write() error {
+	return nil
+}
+
+type Error struct {
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+type Store interface {
+	// Get gets something.
+	//
+	// Errors:
+	//
+	//    - docs-not-found -- if nothing was found
+	Get() error
+
+	Put() error
+}
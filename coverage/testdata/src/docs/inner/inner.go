@@ -0,0 +1,8 @@
+package inner
+
+// Check checks something.
+//
+// Errors: none
+func Check() error {
+	return nil
+}
@@ -0,0 +1,14 @@
+package tested_test
+
+import (
+	"testing"
+
+	"github.com/serum-errors/go-serum-analyzer/serumtest"
+	"tested"
+)
+
+func TestClose(t *testing.T) {
+	store := &tested.Store{}
+	err := store.Close()
+	serumtest.ExpectCode(t, err, "tested-busy")
+}
@@ -0,0 +1,41 @@
+package tested
+
+// Open opens something.
+//
+// Errors:
+//
+//    - tested-missing -- if nothing was found
+//    - tested-denied -- if access was denied
+func Open(name string) error {
+	if name == "" {
+		return &Error{"tested-missing"}
+	}
+	return &Error{"tested-denied"}
+}
+
+// Close closes something.
+//
+// Errors:
+//
+//    - tested-busy -- if it is still in use
+func (s *Store) Close() error {
+	return &Error{"tested-busy"}
+}
+
+type Store struct{}
+
+type Error struct {
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+type Closer interface {
+	// Close closes something.
+	//
+	// Errors:
+	//
+	//    - tested-busy -- if it is still in use
+	Close() error
+}
@@ -0,0 +1,11 @@
+package tested
+
+import (
+	"testing"
+
+	"github.com/serum-errors/go-serum-analyzer/serumtest"
+)
+
+func TestOpen(t *testing.T) {
+	serumtest.ExpectCode(t, Open(""), "tested-missing")
+}
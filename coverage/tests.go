@@ -0,0 +1,97 @@
+package coverage
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+)
+
+// UntestedCode is an error code declared by a function, that no test expects with serumtest.ExpectCode.
+type UntestedCode struct {
+	Package  string `json:"package"`
+	Function string `json:"function"`
+	Code     string `json:"code"`
+}
+
+// functionKey identifies a function across the test variants of its package.
+type functionKey struct {
+	pkg, name string
+}
+
+// Untested returns the error codes declared by the functions and methods of the given packages,
+// that are not expected by any test. The result is sorted by package, function and code.
+// Interface methods are skipped, as their codes are tested through the implementations.
+//
+// The packages have to be loaded with their tests (see packages.Config.Tests), otherwise no code is expected.
+func Untested(result *driver.Result) []UntestedCode {
+	expected := map[functionKey]analysis.CodeSet{}
+	for _, pkg := range result.Packages {
+		analysisResult, ok := pkg.Result.(*analysis.Result)
+		if !ok {
+			continue
+		}
+		for _, expectation := range analysisResult.Expectations() {
+			for _, fn := range expectation.Funcs {
+				key := newFunctionKey(fn)
+				if expected[key] == nil {
+					expected[key] = analysis.Set()
+				}
+				expected[key].Add(expectation.Code)
+			}
+		}
+	}
+
+	seen := map[UntestedCode]struct{}{}
+	var untested []UntestedCode
+	for _, pkg := range result.Packages {
+		if strings.HasSuffix(pkg.PkgPath, "_test") || strings.HasSuffix(pkg.PkgPath, ".test") {
+			continue
+		}
+
+		for _, objectFact := range result.ObjectFacts(pkg.Types) {
+			fact, ok := objectFact.Fact.(*analysis.ErrorCodes)
+			fn, isFunc := objectFact.Object.(*types.Func)
+			if !ok || !isFunc || isInterfaceMethod(fn) {
+				continue
+			}
+
+			key := newFunctionKey(fn)
+			for code := range fact.Codes {
+				if _, ok := expected[key][code]; ok {
+					continue
+				}
+				entry := UntestedCode{key.pkg, key.name, code}
+				if _, ok := seen[entry]; !ok {
+					seen[entry] = struct{}{}
+					untested = append(untested, entry)
+				}
+			}
+		}
+	}
+
+	sort.Slice(untested, func(i, j int) bool {
+		a, b := untested[i], untested[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		if a.Function != b.Function {
+			return a.Function < b.Function
+		}
+		return a.Code < b.Code
+	})
+	return untested
+}
+
+func newFunctionKey(fn *types.Func) functionKey {
+	name, _ := catalog.FunctionName(fn)
+	return functionKey{fn.Pkg().Path(), name}
+}
+
+func isInterfaceMethod(fn *types.Func) bool {
+	recv := fn.Type().(*types.Signature).Recv()
+	return recv != nil && types.IsInterface(recv.Type())
+}
@@ -0,0 +1,297 @@
+// Package driver runs an analyzer on a set of packages within a single process,
+// and gives access to the resulting diagnostics, facts and analysis results.
+//
+// The standard drivers (like singlechecker) only report diagnostics per package.
+// Tools that need a view of a whole module (like the error code catalog) use this package instead.
+package driver
+
+import (
+	"fmt"
+	"go/build"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+// Result contains the outcome of running an analyzer.
+type Result struct {
+	// Packages contains the packages matched by the patterns, sorted by import path.
+	Packages []*Package
+
+	objectFacts  map[objectFactKey]analysis.Fact
+	packageFacts map[packageFactKey]analysis.Fact
+}
+
+// Package is a package matched by the patterns, together with the results of the analysis.
+type Package struct {
+	*packages.Package
+
+	// Diagnostics contains the diagnostics reported for this package, in the order they were reported.
+	Diagnostics []analysis.Diagnostic
+
+	// Result is the value returned by the Run function of the analyzer.
+	Result interface{}
+}
+
+type (
+	objectFactKey struct {
+		obj types.Object
+		typ reflect.Type
+	}
+
+	packageFactKey struct {
+		pkg *types.Package
+		typ reflect.Type
+	}
+
+	actionKey struct {
+		analyzer *analysis.Analyzer
+		pkg      *packages.Package
+	}
+
+	action struct {
+		diagnostics []analysis.Diagnostic
+		result      interface{}
+		err         error
+	}
+
+	runner struct {
+		result  *Result
+		actions map[actionKey]*action
+		roots   map[*packages.Package]struct{}
+	}
+)
+
+// loadMode contains everything needed to run an analyzer from source.
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+	packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedTypesSizes
+
+// Run loads the packages matching the given patterns and runs the given analyzer on them.
+//
+// The analyzer also runs on all dependencies outside of the standard library, so facts are available across packages.
+// Only diagnostics of the matched packages are kept.
+// The config may be nil; its Mode is always overwritten.
+func Run(analyzer *analysis.Analyzer, config *packages.Config, patterns ...string) (*Result, error) {
+	if config == nil {
+		config = &packages.Config{}
+	}
+	config.Mode = loadMode
+
+	roots, err := packages.Load(config, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("could not load packages: %v", err)
+	}
+	if err := firstPackageError(roots); err != nil {
+		return nil, err
+	}
+
+	r := &runner{
+		result: &Result{
+			objectFacts:  map[objectFactKey]analysis.Fact{},
+			packageFacts: map[packageFactKey]analysis.Fact{},
+		},
+		actions: map[actionKey]*action{},
+		roots:   map[*packages.Package]struct{}{},
+	}
+	for _, pkg := range roots {
+		r.roots[pkg] = struct{}{}
+	}
+
+	for _, pkg := range roots {
+		act := r.run(analyzer, pkg)
+		if act.err != nil {
+			return nil, act.err
+		}
+		r.result.Packages = append(r.result.Packages, &Package{pkg, act.diagnostics, act.result})
+	}
+
+	sort.Slice(r.result.Packages, func(i, j int) bool {
+		return r.result.Packages[i].PkgPath < r.result.Packages[j].PkgPath
+	})
+	return r.result, nil
+}
+
+// firstPackageError returns the first error found in the given packages or their dependencies.
+func firstPackageError(roots []*packages.Package) error {
+	var result error
+	packages.Visit(roots, nil, func(pkg *packages.Package) {
+		if result == nil && len(pkg.Errors) > 0 {
+			result = pkg.Errors[0]
+		}
+	})
+	return result
+}
+
+// run runs the given analyzer on the given package, after running it on all dependencies.
+// Required analyzers are run first. The results are cached.
+func (r *runner) run(analyzer *analysis.Analyzer, pkg *packages.Package) *action {
+	key := actionKey{analyzer, pkg}
+	if act, ok := r.actions[key]; ok {
+		return act
+	}
+	act := &action{}
+	r.actions[key] = act
+
+	_, isRoot := r.roots[pkg]
+	if !isRoot && isStandardLibrary(pkg) {
+		return act // The standard library does not declare error codes.
+	}
+
+	if len(analyzer.FactTypes) > 0 {
+		for _, imported := range sortedImports(pkg) {
+			if dep := r.run(analyzer, imported); dep.err != nil {
+				act.err = dep.err
+				return act
+			}
+		}
+	}
+
+	resultOf := map[*analysis.Analyzer]interface{}{}
+	for _, required := range analyzer.Requires {
+		requiredAct := r.run(required, pkg)
+		if requiredAct.err != nil {
+			act.err = requiredAct.err
+			return act
+		}
+		resultOf[required] = requiredAct.result
+	}
+
+	pass := &analysis.Pass{
+		Analyzer:     analyzer,
+		Fset:         pkg.Fset,
+		Files:        pkg.Syntax,
+		OtherFiles:   pkg.OtherFiles,
+		IgnoredFiles: pkg.IgnoredFiles,
+		Pkg:          pkg.Types,
+		TypesInfo:    pkg.TypesInfo,
+		TypesSizes:   pkg.TypesSizes,
+		ResultOf:     resultOf,
+		Report: func(diagnostic analysis.Diagnostic) {
+			act.diagnostics = append(act.diagnostics, diagnostic)
+		},
+		ImportObjectFact: r.result.ObjectFact,
+		ImportPackageFact: func(pkg *types.Package, fact analysis.Fact) bool {
+			return r.result.PackageFact(pkg, fact)
+		},
+		ExportObjectFact: func(obj types.Object, fact analysis.Fact) {
+			if obj.Pkg() != pkg.Types {
+				panic(fmt.Sprintf("analyzer %s exported fact for object %s of another package", analyzer.Name, obj))
+			}
+			r.result.objectFacts[objectFactKey{obj, reflect.TypeOf(fact)}] = fact
+		},
+		ExportPackageFact: func(fact analysis.Fact) {
+			r.result.packageFacts[packageFactKey{pkg.Types, reflect.TypeOf(fact)}] = fact
+		},
+		AllObjectFacts: func() []analysis.ObjectFact {
+			return r.result.allObjectFacts(nil)
+		},
+		AllPackageFacts: func() []analysis.PackageFact {
+			return r.result.allPackageFacts()
+		},
+	}
+
+	act.result, act.err = analyzer.Run(pass)
+	if act.err != nil {
+		act.err = fmt.Errorf("analysis of package %q failed: %v", pkg.PkgPath, act.err)
+	}
+	if !isRoot {
+		act.diagnostics = nil
+	}
+	return act
+}
+
+// sortedImports returns the imports of the given package, sorted by import path.
+func sortedImports(pkg *packages.Package) []*packages.Package {
+	paths := make([]string, 0, len(pkg.Imports))
+	for path := range pkg.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	result := make([]*packages.Package, 0, len(paths))
+	for _, path := range paths {
+		result = append(result, pkg.Imports[path])
+	}
+	return result
+}
+
+// isStandardLibrary checks if the given package belongs to the standard library, by looking where its files are located.
+func isStandardLibrary(pkg *packages.Package) bool {
+	if len(pkg.GoFiles) == 0 {
+		return pkg.PkgPath == "unsafe"
+	}
+	goroot := filepath.Join(build.Default.GOROOT, "src") + string(filepath.Separator)
+	return strings.HasPrefix(pkg.GoFiles[0], goroot)
+}
+
+// ObjectFact retrieves the fact of the given type for the given object.
+// It works like analysis.Pass.ImportObjectFact.
+func (result *Result) ObjectFact(obj types.Object, fact analysis.Fact) bool {
+	found, ok := result.objectFacts[objectFactKey{obj, reflect.TypeOf(fact)}]
+	if !ok {
+		return false
+	}
+	reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(found).Elem())
+	return true
+}
+
+// PackageFact retrieves the fact of the given type for the given package.
+// It works like analysis.Pass.ImportPackageFact.
+func (result *Result) PackageFact(pkg *types.Package, fact analysis.Fact) bool {
+	found, ok := result.packageFacts[packageFactKey{pkg, reflect.TypeOf(fact)}]
+	if !ok {
+		return false
+	}
+	reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(found).Elem())
+	return true
+}
+
+// ObjectFacts returns all object facts exported for objects of the given package,
+// sorted by the position of the objects.
+func (result *Result) ObjectFacts(pkg *types.Package) []analysis.ObjectFact {
+	return result.allObjectFacts(pkg)
+}
+
+// allObjectFacts returns the object facts of the given package (or all packages if nil),
+// sorted by package path and position of the objects.
+func (result *Result) allObjectFacts(pkg *types.Package) []analysis.ObjectFact {
+	var facts []analysis.ObjectFact
+	for key, fact := range result.objectFacts {
+		if pkg == nil || key.obj.Pkg() == pkg {
+			facts = append(facts, analysis.ObjectFact{Object: key.obj, Fact: fact})
+		}
+	}
+
+	sort.Slice(facts, func(i, j int) bool {
+		a, b := facts[i], facts[j]
+		if a.Object.Pkg().Path() != b.Object.Pkg().Path() {
+			return a.Object.Pkg().Path() < b.Object.Pkg().Path()
+		}
+		if a.Object.Pos() != b.Object.Pos() {
+			return a.Object.Pos() < b.Object.Pos()
+		}
+		return reflect.TypeOf(a.Fact).String() < reflect.TypeOf(b.Fact).String()
+	})
+	return facts
+}
+
+func (result *Result) allPackageFacts() []analysis.PackageFact {
+	var facts []analysis.PackageFact
+	for key, fact := range result.packageFacts {
+		facts = append(facts, analysis.PackageFact{Package: key.pkg, Fact: fact})
+	}
+
+	sort.Slice(facts, func(i, j int) bool {
+		a, b := facts[i], facts[j]
+		if a.Package.Path() != b.Package.Path() {
+			return a.Package.Path() < b.Package.Path()
+		}
+		return reflect.TypeOf(a.Fact).String() < reflect.TypeOf(b.Fact).String()
+	})
+	return facts
+}
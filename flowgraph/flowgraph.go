@@ -0,0 +1,177 @@
+// Package flowgraph builds a graph of how error codes flow through a set of packages.
+//
+// Nodes are functions, methods, interface methods, error constructors and error types.
+// An edge from A to B means that codes flow from A into B: B calls A or creates A, and may return the codes of the edge.
+// The graph is built from an error code catalog (see package catalog), and can be written in the DOT or GraphML format.
+// The edges are the origins of the codes in the catalog, which the analyzer resolves from the returned errors,
+// so calls whose errors are handled or replaced are not edges.
+package flowgraph
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+)
+
+// KindConstructor is the kind of nodes for error constructors.
+// Other nodes use the kinds of package catalog.
+const KindConstructor = "error-constructor"
+
+// Graph is a directed graph of error code flows.
+type Graph struct {
+	Nodes []*Node // sorted by package and name
+	Edges []*Edge // sorted by source and target
+}
+
+// Node is a function or error type.
+type Node struct {
+	ID      string // qualified name, e.g. "example.org/store.(*Store).Get"
+	Package string
+	Name    string
+	Kind    string
+}
+
+// Edge means that the codes flow from the node From into the node To.
+type Edge struct {
+	From, To *Node
+	Codes    []string // sorted
+}
+
+// Filter restricts a graph to the flows of interest. The zero value matches everything.
+type Filter struct {
+	// Codes are glob patterns (as in path.Match, e.g. "db-error-*") of the codes to include.
+	// If empty, all codes are included.
+	Codes []string
+
+	// Packages are import path patterns (e.g. "example.org/api/...") of the packages to include.
+	// Edges are included, if their source or target belongs to a matching package.
+	// If empty, all packages are included.
+	Packages []string
+}
+
+// Validate checks if all patterns of the filter are valid.
+func (filter *Filter) Validate() error {
+	for _, pattern := range filter.Codes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid code pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (filter *Filter) matchesCode(code string) bool {
+	if len(filter.Codes) == 0 {
+		return true
+	}
+	for _, pattern := range filter.Codes {
+		if ok, _ := path.Match(pattern, code); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (filter *Filter) matchesPackage(pkgPath string) bool {
+	if len(filter.Packages) == 0 {
+		return true
+	}
+	for _, pattern := range filter.Packages {
+		if analysis.MatchPackagePattern(pattern, pkgPath) {
+			return true
+		}
+	}
+	return false
+}
+
+type edgeKey struct {
+	from, to string
+}
+
+// New builds the graph of all code flows in the catalog, that match the given filter.
+// Nodes without any matching edge are not part of the graph.
+func New(c *catalog.Catalog, filter Filter) *Graph {
+	nodes := map[string]*Node{}
+	node := func(pkgPath, name, kind string) *Node {
+		id := pkgPath + "." + name
+		if existing, ok := nodes[id]; ok {
+			return existing
+		}
+		result := &Node{id, pkgPath, name, kind}
+		nodes[id] = result
+		return result
+	}
+
+	// Create the nodes of all functions first, so they get the kind from the catalog.
+	for _, pkg := range c.Packages {
+		for _, fn := range pkg.Functions {
+			kind := fn.Kind
+			if fn.Constructor != nil {
+				kind = KindConstructor
+			}
+			node(pkg.Path, fn.Name, kind)
+		}
+	}
+
+	edgeCodes := map[edgeKey]map[string]struct{}{}
+	for _, pkg := range c.Packages {
+		for _, fn := range pkg.Functions {
+			to := nodes[pkg.Path+"."+fn.Name]
+			for _, code := range fn.Codes {
+				if !filter.matchesCode(code.Code) {
+					continue
+				}
+
+				for _, origin := range code.Origins {
+					if !filter.matchesPackage(origin.Package) && !filter.matchesPackage(pkg.Path) {
+						continue
+					}
+
+					from := node(origin.Package, origin.Name, origin.Kind)
+					key := edgeKey{from.ID, to.ID}
+					if edgeCodes[key] == nil {
+						edgeCodes[key] = map[string]struct{}{}
+					}
+					edgeCodes[key][code.Code] = struct{}{}
+				}
+			}
+		}
+	}
+
+	graph := &Graph{}
+	used := map[string]struct{}{}
+	for key, codes := range edgeCodes {
+		edge := &Edge{From: nodes[key.from], To: nodes[key.to]}
+		for code := range codes {
+			edge.Codes = append(edge.Codes, code)
+		}
+		sort.Strings(edge.Codes)
+		graph.Edges = append(graph.Edges, edge)
+		used[key.from] = struct{}{}
+		used[key.to] = struct{}{}
+	}
+
+	for id := range used {
+		graph.Nodes = append(graph.Nodes, nodes[id])
+	}
+
+	// Sort by package first, so the nodes of a package are adjacent:
+	// sorting by ID would put "a/b.c.F" between "a/b.A" and "a/b.g".
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		a, b := graph.Nodes[i], graph.Nodes[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Name < b.Name
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From.ID != b.From.ID {
+			return a.From.ID < b.From.ID
+		}
+		return a.To.ID < b.To.ID
+	})
+	return graph
+}
@@ -0,0 +1,186 @@
+package flowgraph
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+)
+
+func testCatalog() *catalog.Catalog {
+	return &catalog.Catalog{Packages: []*catalog.Package{
+		{
+			Path: "shop",
+			Functions: []*catalog.Function{
+				{Name: "Buy", Kind: catalog.KindFunction, Exported: true, Codes: []*catalog.Code{
+					{Code: "inventory-insufficient", Origins: []*catalog.Origin{{Package: "shop/inventory", Name: "Take", Kind: catalog.KindFunction}}},
+					{Code: "inventory-not-found", Origins: []*catalog.Origin{{Package: "shop/inventory", Name: "Take", Kind: catalog.KindFunction}}},
+				}},
+				{Name: "memoryStore.Get", Kind: catalog.KindMethod, Codes: []*catalog.Code{
+					{Code: "inventory-not-found", Origins: []*catalog.Origin{{Package: "shop/inventory", Name: "ErrNotFound", Kind: catalog.KindErrorType}}},
+				}},
+			},
+		},
+		{
+			Path: "shop/inventory",
+			Functions: []*catalog.Function{
+				{Name: "NewError", Kind: catalog.KindFunction, Exported: true, Codes: []*catalog.Code{},
+					Constructor: &catalog.Constructor{Param: "code"}},
+				{Name: "Take", Kind: catalog.KindFunction, Exported: true, Codes: []*catalog.Code{
+					{Code: "inventory-insufficient", Origins: []*catalog.Origin{{Package: "shop/inventory", Name: "NewError", Kind: catalog.KindFunction}}},
+					{Code: "inventory-not-found", Origins: []*catalog.Origin{{Package: "shop/inventory", Name: "Store.Get", Kind: catalog.KindInterfaceMethod}}},
+				}},
+			},
+		},
+	}}
+}
+
+// edgeStrings returns the edges of the graph in the form "from -> to: codes".
+func edgeStrings(graph *Graph) []string {
+	result := []string{}
+	for _, edge := range graph.Edges {
+		result = append(result, edge.From.ID+" -> "+edge.To.ID+": "+strings.Join(edge.Codes, ","))
+	}
+	return result
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{"all", Filter{}, []string{
+			"shop/inventory.ErrNotFound -> shop.memoryStore.Get: inventory-not-found",
+			"shop/inventory.NewError -> shop/inventory.Take: inventory-insufficient",
+			"shop/inventory.Store.Get -> shop/inventory.Take: inventory-not-found",
+			"shop/inventory.Take -> shop.Buy: inventory-insufficient,inventory-not-found",
+		}},
+		{"code", Filter{Codes: []string{"*-not-found"}}, []string{
+			"shop/inventory.ErrNotFound -> shop.memoryStore.Get: inventory-not-found",
+			"shop/inventory.Store.Get -> shop/inventory.Take: inventory-not-found",
+			"shop/inventory.Take -> shop.Buy: inventory-not-found",
+		}},
+		{"package", Filter{Packages: []string{"shop"}}, []string{
+			"shop/inventory.ErrNotFound -> shop.memoryStore.Get: inventory-not-found",
+			"shop/inventory.Take -> shop.Buy: inventory-insufficient,inventory-not-found",
+		}},
+		{"package and code", Filter{Codes: []string{"inventory-insufficient"}, Packages: []string{"shop/..."}}, []string{
+			"shop/inventory.NewError -> shop/inventory.Take: inventory-insufficient",
+			"shop/inventory.Take -> shop.Buy: inventory-insufficient",
+		}},
+		{"no match", Filter{Codes: []string{"db-*"}}, []string{}},
+	}
+
+	for _, test := range tests {
+		graph := New(testCatalog(), test.filter)
+		if actual := edgeStrings(graph); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s: unexpected edges:\n%s\nexpected:\n%s", test.name, strings.Join(actual, "\n"), strings.Join(test.expected, "\n"))
+		}
+	}
+
+	graph := New(testCatalog(), Filter{Codes: []string{"inventory-insufficient"}})
+	kinds := map[string]string{}
+	for _, node := range graph.Nodes {
+		kinds[node.ID] = node.Kind
+	}
+	expectedKinds := map[string]string{
+		"shop.Buy":                catalog.KindFunction,
+		"shop/inventory.NewError": KindConstructor,
+		"shop/inventory.Take":     catalog.KindFunction,
+	}
+	if !reflect.DeepEqual(kinds, expectedKinds) {
+		t.Errorf("unexpected nodes: %v", kinds)
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	filter := Filter{Codes: []string{"db-["}}
+	if err := filter.Validate(); err == nil {
+		t.Errorf("Validate should reject the code pattern %q", filter.Codes[0])
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	graph := New(testCatalog(), Filter{Codes: []string{"inventory-insufficient"}})
+
+	var buffer bytes.Buffer
+	if err := graph.WriteDOT(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `digraph errors {
+	rankdir=LR;
+	subgraph cluster_0 {
+		label="shop";
+		"shop.Buy" [label="Buy", shape=box];
+	}
+	subgraph cluster_1 {
+		label="shop/inventory";
+		"shop/inventory.NewError" [label="NewError", shape=box, style="rounded,bold"];
+		"shop/inventory.Take" [label="Take", shape=box];
+	}
+	"shop/inventory.NewError" -> "shop/inventory.Take" [label="inventory-insufficient"];
+	"shop/inventory.Take" -> "shop.Buy" [label="inventory-insufficient"];
+}
+`
+	if actual := buffer.String(); actual != expected {
+		t.Errorf("unexpected DOT output:\n%s", actual)
+	}
+}
+
+func TestWriteDOTPackagePrefix(t *testing.T) {
+	c := &catalog.Catalog{Packages: []*catalog.Package{
+		{
+			Path: "a/b",
+			Functions: []*catalog.Function{
+				{Name: "A", Kind: catalog.KindFunction, Codes: []*catalog.Code{
+					{Code: "b-failed", Origins: []*catalog.Origin{{Package: "a/b.c", Name: "F", Kind: catalog.KindFunction}}},
+				}},
+				{Name: "g", Kind: catalog.KindFunction, Codes: []*catalog.Code{
+					{Code: "b-failed", Origins: []*catalog.Origin{{Package: "a/b", Name: "A", Kind: catalog.KindFunction}}},
+				}},
+			},
+		},
+	}}
+
+	var buffer bytes.Buffer
+	if err := New(c, Filter{}).WriteDOT(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, label := range []string{`label="a/b";`, `label="a/b.c";`} {
+		if count := strings.Count(buffer.String(), label); count != 1 {
+			t.Errorf("expected a single cluster with %s but found %d:\n%s", label, count, buffer.String())
+		}
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	graph := New(testCatalog(), Filter{})
+
+	var buffer bytes.Buffer
+	if err := graph.WriteGraphML(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	var document graphML
+	if err := xml.Unmarshal(buffer.Bytes(), &document); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+	if len(document.Graph.Nodes) != len(graph.Nodes) || len(document.Graph.Edges) != len(graph.Edges) {
+		t.Fatalf("expected %d nodes and %d edges but found %d and %d",
+			len(graph.Nodes), len(graph.Edges), len(document.Graph.Nodes), len(document.Graph.Edges))
+	}
+
+	edge := document.Graph.Edges[len(document.Graph.Edges)-1]
+	if edge.Source != "shop/inventory.Take" || edge.Target != "shop.Buy" {
+		t.Errorf("unexpected last edge %s -> %s", edge.Source, edge.Target)
+	}
+	if data := edge.Data; len(data) != 1 || data[0].Value != "inventory-insufficient inventory-not-found" {
+		t.Errorf("unexpected edge data %v", data)
+	}
+}
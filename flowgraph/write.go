@@ -0,0 +1,138 @@
+package flowgraph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// dotShapes maps node kinds to the shapes used in DOT graphs.
+var dotShapes = map[string]string{
+	KindConstructor:    `shape=box, style="rounded,bold"`,
+	"function":         "shape=box",
+	"method":           "shape=box",
+	"interface-method": `shape=box, style=dashed`,
+	"error-type":       "shape=ellipse",
+}
+
+// WriteDOT writes the graph in the DOT format of Graphviz.
+// Nodes are grouped in a cluster per package.
+func (graph *Graph) WriteDOT(w io.Writer) error {
+	var builder strings.Builder
+	builder.WriteString("digraph errors {\n")
+	builder.WriteString("\trankdir=LR;\n")
+
+	var currentPackage string
+	cluster := 0
+	for _, node := range graph.Nodes {
+		if node.Package != currentPackage {
+			if currentPackage != "" {
+				builder.WriteString("\t}\n")
+			}
+			currentPackage = node.Package
+			fmt.Fprintf(&builder, "\tsubgraph cluster_%d {\n", cluster)
+			fmt.Fprintf(&builder, "\t\tlabel=%s;\n", strconv.Quote(node.Package))
+			cluster++
+		}
+
+		shape, ok := dotShapes[node.Kind]
+		if !ok {
+			shape = "shape=box"
+		}
+		fmt.Fprintf(&builder, "\t\t%s [label=%s, %s];\n", strconv.Quote(node.ID), strconv.Quote(node.Name), shape)
+	}
+	if currentPackage != "" {
+		builder.WriteString("\t}\n")
+	}
+
+	for _, edge := range graph.Edges {
+		label := strings.Join(edge.Codes, "\n")
+		fmt.Fprintf(&builder, "\t%s -> %s [label=%s];\n", strconv.Quote(edge.From.ID), strconv.Quote(edge.To.ID), strconv.Quote(label))
+	}
+
+	builder.WriteString("}\n")
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+type (
+	graphML struct {
+		XMLName xml.Name     `xml:"graphml"`
+		XMLNS   string       `xml:"xmlns,attr"`
+		Keys    []graphMLKey `xml:"key"`
+		Graph   graphMLGraph `xml:"graph"`
+	}
+
+	graphMLKey struct {
+		ID       string `xml:"id,attr"`
+		For      string `xml:"for,attr"`
+		AttrName string `xml:"attr.name,attr"`
+		AttrType string `xml:"attr.type,attr"`
+	}
+
+	graphMLGraph struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	}
+
+	graphMLNode struct {
+		ID   string        `xml:"id,attr"`
+		Data []graphMLData `xml:"data"`
+	}
+
+	graphMLEdge struct {
+		Source string        `xml:"source,attr"`
+		Target string        `xml:"target,attr"`
+		Data   []graphMLData `xml:"data"`
+	}
+
+	graphMLData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// WriteGraphML writes the graph in the GraphML format.
+// Nodes have the attributes "package", "name" and "kind", edges have the attribute "codes",
+// which contains the codes separated by spaces.
+func (graph *Graph) WriteGraphML(w io.Writer) error {
+	document := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{"package", "node", "package", "string"},
+			{"name", "node", "name", "string"},
+			{"kind", "node", "kind", "string"},
+			{"codes", "edge", "codes", "string"},
+		},
+		Graph: graphMLGraph{ID: "errors", EdgeDefault: "directed"},
+	}
+
+	for _, node := range graph.Nodes {
+		document.Graph.Nodes = append(document.Graph.Nodes, graphMLNode{
+			ID:   node.ID,
+			Data: []graphMLData{{"package", node.Package}, {"name", node.Name}, {"kind", node.Kind}},
+		})
+	}
+	for _, edge := range graph.Edges {
+		document.Graph.Edges = append(document.Graph.Edges, graphMLEdge{
+			Source: edge.From.ID,
+			Target: edge.To.ID,
+			Data:   []graphMLData{{"codes", strings.Join(edge.Codes, " ")}},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "\t")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
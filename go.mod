@@ -2,7 +2,10 @@ module github.com/serum-errors/go-serum-analyzer
 
 go 1.17
 
-require golang.org/x/tools v0.1.5
+require (
+	golang.org/x/tools v0.1.5
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	golang.org/x/mod v0.5.0 // indirect
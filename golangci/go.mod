@@ -0,0 +1,16 @@
+module github.com/serum-errors/go-serum-analyzer/golangci
+
+go 1.23.0
+
+require (
+	github.com/golangci/plugin-module-register v0.1.2
+	github.com/serum-errors/go-serum-analyzer v0.0.0
+	golang.org/x/tools v0.32.0
+)
+
+require gopkg.in/yaml.v3 v3.0.1 // indirect
+
+// The analyzer is taken from the parent directory, so the plugin has to be built from a local checkout
+// (a "path:" plugin in .custom-gcl.yml). Replace directives are ignored in dependencies,
+// so the module cannot be used as a remote plugin with a version.
+replace github.com/serum-errors/go-serum-analyzer => ../
@@ -0,0 +1,58 @@
+// Package golangci registers the error code analyzer as a golangci-lint module plugin.
+//
+// The plugin is built into a custom golangci-lint binary with "golangci-lint custom",
+// using a .custom-gcl.yml file referring to a local checkout of the repository:
+//
+//	version: v2.1.6
+//	plugins:
+//	  - module: github.com/serum-errors/go-serum-analyzer/golangci
+//	    path: ./go-serum-analyzer/golangci
+//
+// The module requires the analyzer module through a replace directive pointing to the parent directory,
+// which Go ignores in dependencies. So the plugin cannot be fetched as a remote module with a version.
+//
+// The settings of the linter are the fields of analysis.Settings:
+//
+//	linters:
+//	  enable:
+//	    - serum
+//	  settings:
+//	    custom:
+//	      serum:
+//	        type: module
+//	        settings:
+//	          strict: true
+//	          config: .serum.yaml
+package golangci
+
+import (
+	"github.com/golangci/plugin-module-register/register"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+)
+
+func init() {
+	register.Plugin("serum", New)
+}
+
+// New creates the plugin from the settings of the golangci-lint configuration.
+func New(settings any) (register.LinterPlugin, error) {
+	decoded, err := register.DecodeSettings[analysis.Settings](settings)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin{decoded}, nil
+}
+
+type plugin struct {
+	settings analysis.Settings
+}
+
+func (p *plugin) BuildAnalyzers() ([]*goanalysis.Analyzer, error) {
+	return []*goanalysis.Analyzer{analysis.NewAnalyzer(p.settings)}, nil
+}
+
+func (p *plugin) GetLoadMode() string {
+	return register.LoadModeTypesInfo
+}
@@ -0,0 +1,257 @@
+// Package reference generates API error reference pages from an error code catalog.
+//
+// One page is generated per package, listing each exported function with the error codes it may return,
+// their descriptions and the called functions they originate from.
+// An index page lists all packages and all error codes.
+// Codes and functions are cross-linked, both within and across pages.
+package reference
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+)
+
+// Output formats of the reference pages.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// File is a generated reference page.
+type File struct {
+	Name    string // file name, relative to the output directory
+	Content []byte
+}
+
+type (
+	// link is a reference to a function or code. If Href is empty, the target is not part of the reference.
+	link struct {
+		Text string
+		Href string
+	}
+
+	page struct {
+		Path       string
+		File       string
+		Index      string
+		Functions  []*function
+		ErrorTypes []*catalog.ErrorType
+		Codes      []*codeUsage
+	}
+
+	function struct {
+		Anchor      string
+		Name        string
+		Kind        string
+		Codes       []*code
+		Constructor *catalog.Constructor
+	}
+
+	code struct {
+		Code        string
+		Anchor      string
+		Description string
+		Origins     []link
+	}
+
+	// codeUsage lists the functions returning a code.
+	codeUsage struct {
+		Code      string
+		Anchor    string
+		Functions []link
+	}
+
+	index struct {
+		Packages []link
+		Codes    []*codeUsage
+	}
+)
+
+// Generate creates the reference pages for all packages in the given catalog,
+// and an index page, in the given format.
+func Generate(c *catalog.Catalog, format string) ([]*File, error) {
+	var ext string
+	var render func(name string, data interface{}) ([]byte, error)
+	switch format {
+	case FormatMarkdown:
+		ext = ".md"
+		render = renderMarkdown
+	case FormatHTML:
+		ext = ".html"
+		render = renderHTML
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	pages := buildPages(c, ext)
+
+	var files []*File
+	for _, p := range pages.list {
+		content, err := render("page", p)
+		if err != nil {
+			return nil, fmt.Errorf("could not render page of package %q: %v", p.Path, err)
+		}
+		files = append(files, &File{p.File, content})
+	}
+
+	content, err := render("index", pages.index)
+	if err != nil {
+		return nil, fmt.Errorf("could not render index: %v", err)
+	}
+	files = append(files, &File{"index" + ext, content})
+	return files, nil
+}
+
+// Write writes the given files to the given directory, which is created if needed.
+func Write(dir string, files []*File) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, file.Name), file.Content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type pageSet struct {
+	list  []*page
+	index *index
+}
+
+// buildPages prepares the data of all pages, resolving the links between them.
+func buildPages(c *catalog.Catalog, ext string) *pageSet {
+	result := &pageSet{index: &index{}}
+	indexFile := "index" + ext
+
+	// Collect the targets of all links first: the exported functions of all packages, by qualified name.
+	targets := map[string]string{}
+	for _, pkg := range c.Packages {
+		file := pageFileName(pkg.Path, ext)
+		for _, fn := range pkg.Functions {
+			if fn.Exported {
+				targets[pkg.Path+"."+fn.Name] = file + "#" + functionAnchor(fn.Name)
+			}
+		}
+	}
+
+	allCodes := map[string]*codeUsage{}
+	for _, pkg := range c.Packages {
+		p := &page{Path: pkg.Path, File: pageFileName(pkg.Path, ext), Index: indexFile}
+		pageCodes := map[string]*codeUsage{}
+
+		for _, fn := range pkg.Functions {
+			if !fn.Exported {
+				continue
+			}
+
+			f := &function{
+				Anchor:      functionAnchor(fn.Name),
+				Name:        fn.Name,
+				Kind:        fn.Kind,
+				Constructor: fn.Constructor,
+			}
+			for _, fnCode := range fn.Codes {
+				origins := originLinks(pkg.Path, p.File, fnCode.Origins, targets)
+				f.Codes = append(f.Codes, &code{fnCode.Code, codeAnchor(fnCode.Code), fnCode.Description, origins})
+
+				addUsage(pageCodes, fnCode.Code, link{fn.Name, "#" + f.Anchor})
+				addUsage(allCodes, fnCode.Code, link{pkg.Path + "." + fn.Name, p.File + "#" + f.Anchor})
+			}
+			p.Functions = append(p.Functions, f)
+		}
+
+		for _, errorType := range pkg.ErrorTypes {
+			if errorType.Exported {
+				p.ErrorTypes = append(p.ErrorTypes, errorType)
+			}
+		}
+
+		p.Codes = sortedUsages(pageCodes)
+		result.list = append(result.list, p)
+		result.index.Packages = append(result.index.Packages, link{pkg.Path, p.File})
+	}
+
+	result.index.Codes = sortedUsages(allCodes)
+	return result
+}
+
+func addUsage(usages map[string]*codeUsage, code string, function link) {
+	usage, ok := usages[code]
+	if !ok {
+		usage = &codeUsage{Code: code, Anchor: codeAnchor(code)}
+		usages[code] = usage
+	}
+	usage.Functions = append(usage.Functions, function)
+}
+
+func sortedUsages(usages map[string]*codeUsage) []*codeUsage {
+	result := make([]*codeUsage, 0, len(usages))
+	for _, usage := range usages {
+		result = append(result, usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+	return result
+}
+
+// originLinks creates links to the given origins, relative to the page of the given package.
+// Origins in the same package are named without the package path.
+func originLinks(pkgPath, file string, origins []*catalog.Origin, targets map[string]string) []link {
+	var result []link
+	for _, origin := range origins {
+		text := origin.Package + "." + origin.Name
+		if origin.Package == pkgPath {
+			text = origin.Name
+		}
+
+		href := targets[origin.Package+"."+origin.Name]
+		if strings.HasPrefix(href, file+"#") {
+			href = strings.TrimPrefix(href, file)
+		}
+		result = append(result, link{text, href})
+	}
+	return result
+}
+
+// pageFileName returns the name of the page for the package with the given import path.
+func pageFileName(pkgPath, ext string) string {
+	return strings.Replace(pkgPath, "/", "_", -1) + ext
+}
+
+// functionAnchor returns the anchor of a function with the given name, like "(*Type).Method".
+func functionAnchor(name string) string {
+	name = strings.NewReplacer("(", "", ")", "", "*", "").Replace(name)
+	return "func-" + name
+}
+
+// codeAnchor returns the anchor of the given error code. Valid error codes only contain characters allowed in anchors.
+func codeAnchor(code string) string {
+	return "code-" + code
+}
+
+func renderMarkdown(name string, data interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := markdownTemplates.ExecuteTemplate(&buffer, name, data)
+	return buffer.Bytes(), err
+}
+
+func renderHTML(name string, data interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := htmlTemplates.ExecuteTemplate(&buffer, name, data)
+	return buffer.Bytes(), err
+}
+
+// escapeCell escapes text for use in a cell of a Markdown table.
+func escapeCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(text)
+}
@@ -0,0 +1,105 @@
+package reference
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+)
+
+var testCatalog = &catalog.Catalog{Packages: []*catalog.Package{
+	{
+		Path: "example.org/shop",
+		Functions: []*catalog.Function{
+			{Name: "Buy", Kind: catalog.KindFunction, Exported: true, Codes: []*catalog.Code{
+				{Code: "shop-sold-out", Description: "if the item | is sold out", Origins: []*catalog.Origin{{Package: "example.org/shop/store", Name: "(*Store).Take", Kind: catalog.KindMethod}}},
+			}},
+			{Name: "helper", Kind: catalog.KindFunction, Codes: []*catalog.Code{{Code: "shop-sold-out"}}},
+		},
+	},
+	{
+		Path: "example.org/shop/store",
+		Functions: []*catalog.Function{
+			{Name: "(*Store).Take", Kind: catalog.KindMethod, Exported: true, Codes: []*catalog.Code{
+				{Code: "shop-sold-out", Description: "if <nothing> is left", Origins: []*catalog.Origin{{Package: "example.org/shop/store", Name: "take", Kind: catalog.KindFunction}}},
+			}},
+		},
+		ErrorTypes: []*catalog.ErrorType{
+			{Name: "Error", Exported: true, Field: "code"},
+		},
+	},
+}}
+
+func generate(t *testing.T, format string) map[string]string {
+	t.Helper()
+	files, err := Generate(testCatalog, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := map[string]string{}
+	for _, file := range files {
+		result[file.Name] = string(file.Content)
+	}
+	return result
+}
+
+func expectContains(t *testing.T, files map[string]string, name string, expected ...string) {
+	t.Helper()
+	content, ok := files[name]
+	if !ok {
+		t.Fatalf("expected file %q to be generated", name)
+	}
+	for _, text := range expected {
+		if !strings.Contains(content, text) {
+			t.Errorf("expected %q to contain %q, but got:\n%s", name, text, content)
+		}
+	}
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	files := generate(t, FormatMarkdown)
+	if len(files) != 3 {
+		t.Errorf("expected 2 package pages and an index, but got %d files", len(files))
+	}
+
+	expectContains(t, files, "example.org_shop.md",
+		"# Errors of Package `example.org/shop`",
+		`### <a id="func-Buy"></a>`+"`Buy`",
+		"| [`shop-sold-out`](#code-shop-sold-out) | if the item \\| is sold out | [`example.org/shop/store.(*Store).Take`](example.org_shop_store.md#func-Store.Take) |",
+		"Returned by: [`Buy`](#func-Buy) ([all packages](index.md#code-shop-sold-out))",
+	)
+	if strings.Contains(files["example.org_shop.md"], "helper") {
+		t.Errorf("unexported functions must not be part of the reference")
+	}
+
+	expectContains(t, files, "example.org_shop_store.md",
+		`### <a id="func-Store.Take"></a>`+"`(*Store).Take`",
+		"| if <nothing> is left | `take` |",
+		"The error code is stored in the field `code`.",
+	)
+
+	expectContains(t, files, "index.md",
+		"* [`example.org/shop`](example.org_shop.md)",
+		"| <a id=\"code-shop-sold-out\"></a>`shop-sold-out` | [`example.org/shop.Buy`](example.org_shop.md#func-Buy), [`example.org/shop/store.(*Store).Take`](example.org_shop_store.md#func-Store.Take) |",
+	)
+}
+
+func TestGenerateHTML(t *testing.T) {
+	files := generate(t, FormatHTML)
+
+	expectContains(t, files, "example.org_shop_store.html",
+		`<h3 id="func-Store.Take"><code>(*Store).Take</code></h3>`,
+		`<td>if &lt;nothing&gt; is left</td>`,
+		`<a href="index.html#code-shop-sold-out">all packages</a>`,
+	)
+	expectContains(t, files, "index.html",
+		`<li><a href="example.org_shop.html"><code>example.org/shop</code></a></li>`,
+	)
+}
+
+func TestGenerateUnknownFormat(t *testing.T) {
+	if _, err := Generate(testCatalog, "pdf"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
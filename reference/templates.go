@@ -0,0 +1,161 @@
+package reference
+
+import (
+	htmltemplate "html/template"
+	"text/template"
+)
+
+var markdownTemplates = template.Must(template.New("").Funcs(template.FuncMap{"cell": escapeCell}).Parse(`
+{{- define "link" }}{{ if .Href }}[` + "`{{ .Text }}`" + `]({{ .Href }}){{ else }}` + "`{{ .Text }}`" + `{{ end }}{{ end }}
+
+{{- define "links" }}{{ range $i, $link := . }}{{ if $i }}, {{ end }}{{ template "link" $link }}{{ end }}{{ end }}
+
+{{- define "page" -}}
+# Errors of Package ` + "`{{ .Path }}`" + `
+
+[Index]({{ .Index }})
+{{- if .Functions }}
+
+## Functions
+{{- range .Functions }}
+
+### <a id="{{ .Anchor }}"></a>` + "`{{ .Name }}`" + `
+{{- if .Constructor }}
+
+Error constructor: the error code is given by the parameter ` + "`{{ .Constructor.Param }}`" + `{{ if .Constructor.Description }} -- {{ .Constructor.Description }}{{ end }}.
+{{- end }}
+{{- if .Codes }}
+
+| Code | Description | Originates from |
+| ---- | ----------- | --------------- |
+{{- range .Codes }}
+| [` + "`{{ .Code }}`" + `](#{{ .Anchor }}) | {{ cell .Description }} | {{ template "links" .Origins }} |
+{{- end }}
+{{- else if not .Constructor }}
+
+Returns no error codes.
+{{- end }}
+{{- end }}
+{{- end }}
+{{- if .ErrorTypes }}
+
+## Error Types
+{{- range .ErrorTypes }}
+
+### ` + "`{{ .Name }}`" + `
+
+{{ if .Field }}The error code is stored in the field ` + "`{{ .Field }}`" + `.{{ else }}Error codes: {{ range $i, $code := .Codes }}{{ if $i }}, {{ end }}` + "`{{ $code }}`" + `{{ end }}{{ end }}
+{{- end }}
+{{- end }}
+{{- if .Codes }}
+
+## Error Codes
+{{- range .Codes }}
+
+### <a id="{{ .Anchor }}"></a>` + "`{{ .Code }}`" + `
+
+Returned by: {{ template "links" .Functions }} ([all packages]({{ $.Index }}#{{ .Anchor }}))
+{{- end }}
+{{- end }}
+{{ end }}
+
+{{- define "index" -}}
+# Error Reference
+
+## Packages
+{{ range .Packages }}
+* {{ template "link" . }}
+{{- end }}
+{{- if .Codes }}
+
+## Error Codes
+
+| Code | Returned by |
+| ---- | ----------- |
+{{- range .Codes }}
+| <a id="{{ .Anchor }}"></a>` + "`{{ .Code }}`" + ` | {{ template "links" .Functions }} |
+{{- end }}
+{{- end }}
+{{ end }}
+`))
+
+var htmlTemplates = htmltemplate.Must(htmltemplate.New("").Parse(`
+{{- define "link" }}{{ if .Href }}<a href="{{ .Href }}"><code>{{ .Text }}</code></a>{{ else }}<code>{{ .Text }}</code>{{ end }}{{ end }}
+
+{{- define "links" }}{{ range $i, $link := . }}{{ if $i }}, {{ end }}{{ template "link" $link }}{{ end }}{{ end }}
+
+{{- define "page" -}}
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Errors of Package {{ .Path }}</title>
+</head>
+<body>
+<h1>Errors of Package <code>{{ .Path }}</code></h1>
+<p><a href="{{ .Index }}">Index</a></p>
+{{- if .Functions }}
+<h2>Functions</h2>
+{{- range .Functions }}
+<h3 id="{{ .Anchor }}"><code>{{ .Name }}</code></h3>
+{{- if .Constructor }}
+<p>Error constructor: the error code is given by the parameter <code>{{ .Constructor.Param }}</code>{{ if .Constructor.Description }} &ndash; {{ .Constructor.Description }}{{ end }}.</p>
+{{- end }}
+{{- if .Codes }}
+<table>
+<tr><th>Code</th><th>Description</th><th>Originates from</th></tr>
+{{- range .Codes }}
+<tr><td><a href="#{{ .Anchor }}"><code>{{ .Code }}</code></a></td><td>{{ .Description }}</td><td>{{ template "links" .Origins }}</td></tr>
+{{- end }}
+</table>
+{{- else if not .Constructor }}
+<p>Returns no error codes.</p>
+{{- end }}
+{{- end }}
+{{- end }}
+{{- if .ErrorTypes }}
+<h2>Error Types</h2>
+{{- range .ErrorTypes }}
+<h3><code>{{ .Name }}</code></h3>
+<p>{{ if .Field }}The error code is stored in the field <code>{{ .Field }}</code>.{{ else }}Error codes: {{ range $i, $code := .Codes }}{{ if $i }}, {{ end }}<code>{{ $code }}</code>{{ end }}{{ end }}</p>
+{{- end }}
+{{- end }}
+{{- if .Codes }}
+<h2>Error Codes</h2>
+{{- range .Codes }}
+<h3 id="{{ .Anchor }}"><code>{{ .Code }}</code></h3>
+<p>Returned by: {{ template "links" .Functions }} (<a href="{{ $.Index }}#{{ .Anchor }}">all packages</a>)</p>
+{{- end }}
+{{- end }}
+</body>
+</html>
+{{ end }}
+
+{{- define "index" -}}
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Error Reference</title>
+</head>
+<body>
+<h1>Error Reference</h1>
+<h2>Packages</h2>
+<ul>
+{{- range .Packages }}
+<li>{{ template "link" . }}</li>
+{{- end }}
+</ul>
+{{- if .Codes }}
+<h2>Error Codes</h2>
+<table>
+<tr><th>Code</th><th>Returned by</th></tr>
+{{- range .Codes }}
+<tr><td id="{{ .Anchor }}"><code>{{ .Code }}</code></td><td>{{ template "links" .Functions }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+</body>
+</html>
+{{ end }}
+`))
@@ -0,0 +1,275 @@
+// Package sarif writes the diagnostics of the error code analyzer in the SARIF 2.1.0 format,
+// which is read by code scanning platforms.
+//
+// Each diagnostic category is a rule, identified by the stable name of the category.
+// Related information of a diagnostic (e.g. the return statements returning undeclared codes)
+// becomes related locations of the result, and suggested fixes become fixes of the result.
+package sarif
+
+import (
+	"encoding/json"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+
+	serum "github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+)
+
+const (
+	version = "2.1.0"
+	schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// columnKind declares how columns are counted: the SARIF default of UTF-16 code units is not used by Go.
+	columnKind = "unicodeCodePoints"
+
+	// srcRoot is the base of all artifact locations within the root directory.
+	srcRoot = "%SRCROOT%"
+)
+
+// Tool describes the tool producing the log.
+type Tool struct {
+	Name           string
+	Version        string // may be empty
+	InformationURI string // may be empty
+}
+
+// The following types are the subset of the SARIF object model used by this package.
+type (
+	Log struct {
+		Version string `json:"version"`
+		Schema  string `json:"$schema"`
+		Runs    []*Run `json:"runs"`
+	}
+
+	Run struct {
+		Tool               ToolComponent               `json:"tool"`
+		OriginalURIBaseIDs map[string]ArtifactLocation `json:"originalUriBaseIds,omitempty"`
+		ColumnKind         string                      `json:"columnKind"`
+		Results            []*Result                   `json:"results"`
+	}
+
+	ToolComponent struct {
+		Driver Driver `json:"driver"`
+	}
+
+	Driver struct {
+		Name           string  `json:"name"`
+		Version        string  `json:"version,omitempty"`
+		InformationURI string  `json:"informationUri,omitempty"`
+		Rules          []*Rule `json:"rules"`
+	}
+
+	Rule struct {
+		ID               string  `json:"id"`
+		ShortDescription Message `json:"shortDescription"`
+	}
+
+	Result struct {
+		RuleID           string             `json:"ruleId"`
+		RuleIndex        int                `json:"ruleIndex"`
+		Level            string             `json:"level"`
+		Message          Message            `json:"message"`
+		Locations        []*Location        `json:"locations"`
+		RelatedLocations []*RelatedLocation `json:"relatedLocations,omitempty"`
+		Fixes            []*Fix             `json:"fixes,omitempty"`
+	}
+
+	Message struct {
+		Text string `json:"text"`
+	}
+
+	Location struct {
+		PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+	}
+
+	RelatedLocation struct {
+		ID               int              `json:"id"`
+		PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+		Message          Message          `json:"message"`
+	}
+
+	PhysicalLocation struct {
+		ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+		Region           Region           `json:"region"`
+	}
+
+	ArtifactLocation struct {
+		URI       string `json:"uri"`
+		URIBaseID string `json:"uriBaseId,omitempty"`
+	}
+
+	Region struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndLine     int `json:"endLine,omitempty"`
+		EndColumn   int `json:"endColumn,omitempty"`
+	}
+
+	Fix struct {
+		Description     Message           `json:"description"`
+		ArtifactChanges []*ArtifactChange `json:"artifactChanges"`
+	}
+
+	ArtifactChange struct {
+		ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+		Replacements     []*Replacement   `json:"replacements"`
+	}
+
+	Replacement struct {
+		DeletedRegion   Region  `json:"deletedRegion"`
+		InsertedContent Message `json:"insertedContent"`
+	}
+)
+
+// New creates a log of all diagnostics in the given result.
+//
+// Files within the given root directory (an absolute path) are referred to relative to the root,
+// all other files by their absolute path.
+func New(tool Tool, result *driver.Result, root string) *Log {
+	categories := serum.Categories()
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	run := &Run{
+		Tool: ToolComponent{Driver{
+			Name:           tool.Name,
+			Version:        tool.Version,
+			InformationURI: tool.InformationURI,
+			Rules:          []*Rule{},
+		}},
+		OriginalURIBaseIDs: map[string]ArtifactLocation{
+			srcRoot: {URI: fileURI(root) + "/"},
+		},
+		ColumnKind: columnKind,
+		Results:    []*Result{},
+	}
+
+	sources := sourceFiles{}
+	ruleIndex := map[string]int{}
+	for i, name := range names {
+		ruleIndex[name] = i
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &Rule{name, Message{categories[name]}})
+	}
+
+	for _, pkg := range result.Packages {
+		for _, diagnostic := range pkg.Diagnostics {
+			index, ok := ruleIndex[diagnostic.Category]
+			if !ok {
+				// Diagnostics of unknown categories get a rule of their own, so they are not attributed to another rule.
+				index = len(run.Tool.Driver.Rules)
+				ruleIndex[diagnostic.Category] = index
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &Rule{diagnostic.Category, Message{diagnostic.Category}})
+			}
+			run.Results = append(run.Results, newResult(pkg.Fset, root, sources, diagnostic, index))
+		}
+	}
+
+	return &Log{Version: version, Schema: schema, Runs: []*Run{run}}
+}
+
+func newResult(fset *token.FileSet, root string, sources sourceFiles, diagnostic analysis.Diagnostic, ruleIndex int) *Result {
+	result := &Result{
+		RuleID:    diagnostic.Category,
+		RuleIndex: ruleIndex,
+		Level:     "warning",
+		Message:   Message{diagnostic.Message},
+		Locations: []*Location{{physicalLocation(fset, root, sources, diagnostic.Pos, diagnostic.End)}},
+	}
+
+	for i, related := range diagnostic.Related {
+		result.RelatedLocations = append(result.RelatedLocations, &RelatedLocation{
+			ID:               i + 1,
+			PhysicalLocation: physicalLocation(fset, root, sources, related.Pos, related.End),
+			Message:          Message{related.Message},
+		})
+	}
+
+	for _, suggestedFix := range diagnostic.SuggestedFixes {
+		fix := &Fix{Description: Message{suggestedFix.Message}}
+		changes := map[string]*ArtifactChange{}
+		for _, edit := range suggestedFix.TextEdits {
+			location := physicalLocation(fset, root, sources, edit.Pos, edit.End)
+			change, ok := changes[location.ArtifactLocation.URI]
+			if !ok {
+				change = &ArtifactChange{ArtifactLocation: location.ArtifactLocation}
+				changes[location.ArtifactLocation.URI] = change
+				fix.ArtifactChanges = append(fix.ArtifactChanges, change)
+			}
+			change.Replacements = append(change.Replacements, &Replacement{location.Region, Message{string(edit.NewText)}})
+		}
+		result.Fixes = append(result.Fixes, fix)
+	}
+
+	return result
+}
+
+// physicalLocation returns the location of the given range.
+// If end is not valid, the region only contains the start position.
+func physicalLocation(fset *token.FileSet, root string, sources sourceFiles, pos, end token.Pos) PhysicalLocation {
+	start := fset.Position(pos)
+	result := PhysicalLocation{
+		ArtifactLocation: artifactLocation(root, start.Filename),
+		Region:           Region{StartLine: start.Line, StartColumn: sources.column(start)},
+	}
+
+	if end.IsValid() {
+		end := fset.Position(end)
+		result.Region.EndLine = end.Line
+		result.Region.EndColumn = sources.column(end)
+	}
+	return result
+}
+
+// sourceFiles caches the contents of source files by file name, which are needed to convert columns.
+// A nil entry means the file could not be read.
+type sourceFiles map[string][]byte
+
+// column returns the column of the given position in unicode code points, as declared by the columnKind of the run.
+// The column of the position counts bytes instead, which is only used if the file can't be read.
+func (sources sourceFiles) column(position token.Position) int {
+	content, ok := sources[position.Filename]
+	if !ok {
+		content, _ = ioutil.ReadFile(position.Filename)
+		sources[position.Filename] = content
+	}
+
+	lineStart := position.Offset - (position.Column - 1)
+	if lineStart < 0 || position.Offset > len(content) {
+		return position.Column
+	}
+	return utf8.RuneCount(content[lineStart:position.Offset]) + 1
+}
+
+func artifactLocation(root, filename string) ArtifactLocation {
+	if relative, err := filepath.Rel(root, filename); err == nil && !strings.HasPrefix(relative, "..") {
+		return ArtifactLocation{URI: (&url.URL{Path: filepath.ToSlash(relative)}).String(), URIBaseID: srcRoot}
+	}
+	return ArtifactLocation{URI: fileURI(filename)}
+}
+
+// fileURI returns the file URI of the given absolute path.
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // Windows paths like "C:/dir"
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// Write writes the log as indented JSON.
+func (log *Log) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
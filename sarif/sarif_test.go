@@ -0,0 +1,132 @@
+package sarif
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+	"golang.org/x/tools/go/packages"
+)
+
+// runTestdata runs the analyzer on the mismatch package of the testdata, and returns the result and the testdata directory.
+func runTestdata(t *testing.T) (*driver.Result, string) {
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &packages.Config{
+		Dir: testdata,
+		Env: append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off", "GOPROXY=off"),
+	}
+	result, err := driver.Run(analysis.Analyzer, config, "mismatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, testdata
+}
+
+func TestNew(t *testing.T) {
+	result, testdata := runTestdata(t)
+
+	log := New(Tool{Name: "go-serum-analyzer"}, result, testdata)
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(analysis.Categories()) {
+		t.Errorf("expected a rule per category, but found %d rules", len(run.Tool.Driver.Rules))
+	}
+	if len(run.Results) != 1 {
+		t.Fatalf("expected 1 result but found %d", len(run.Results))
+	}
+
+	res := run.Results[0]
+	if res.RuleID != "code-mismatch" || run.Tool.Driver.Rules[res.RuleIndex].ID != res.RuleID {
+		t.Errorf("unexpected rule %q with index %d", res.RuleID, res.RuleIndex)
+	}
+
+	location := res.Locations[0].PhysicalLocation
+	if location.ArtifactLocation != (ArtifactLocation{"src/mismatch/mismatch.go", srcRoot}) {
+		t.Errorf("unexpected artifact location %+v", location.ArtifactLocation)
+	}
+	if location.Region != (Region{15, 1, 15, 29}) {
+		t.Errorf("unexpected region %+v", location.Region)
+	}
+
+	var relatedLines []int
+	for _, related := range res.RelatedLocations {
+		relatedLines = append(relatedLines, related.PhysicalLocation.Region.StartLine)
+	}
+	if len(relatedLines) != 2 || relatedLines[0] != 17 || relatedLines[1] != 19 {
+		t.Errorf("expected related locations at the return statements in lines 17 and 19, but found %v", relatedLines)
+	}
+
+	if len(res.Fixes) != 1 {
+		t.Fatalf("expected 1 fix but found %d", len(res.Fixes))
+	}
+	replacements := res.Fixes[0].ArtifactChanges[0].Replacements
+	if len(replacements) != 1 || replacements[0].InsertedContent.Text != "mismatch-not-found" || replacements[0].DeletedRegion.StartLine != 14 {
+		t.Errorf("unexpected replacements %+v", replacements)
+	}
+
+	var buffer bytes.Buffer
+	if err := log.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["version"] != "2.1.0" {
+		t.Errorf("unexpected version %v", decoded["version"])
+	}
+	if run.ColumnKind != "unicodeCodePoints" {
+		t.Errorf("unexpected column kind %q", run.ColumnKind)
+	}
+}
+
+func TestSourceFilesColumn(t *testing.T) {
+	content := "package p\n\nvar s = \"äöü\" + x\n"
+	path := filepath.Join(t.TempDir(), "p.go")
+	if err := ioutil.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	file := fset.AddFile(path, -1, len(content))
+	file.SetLinesForContent([]byte(content))
+
+	sources := sourceFiles{}
+	// The "x" is the 20th byte of the line, but the 17th code point.
+	x := fset.Position(file.Pos(len(content) - 2))
+	if column := sources.column(x); column != 17 {
+		t.Errorf("expected column 17 of %v but got %d", x, column)
+	}
+
+	// Files that can't be read keep the byte column.
+	sources[path] = nil
+	if column := sources.column(x); column != x.Column {
+		t.Errorf("expected byte column %d of an unreadable file but got %d", x.Column, column)
+	}
+}
+
+func TestNewUnknownCategory(t *testing.T) {
+	result, testdata := runTestdata(t)
+	pkg := result.Packages[0]
+	diagnostic := pkg.Diagnostics[0]
+	diagnostic.Category = "other-analyzer"
+	pkg.Diagnostics = append(pkg.Diagnostics, diagnostic)
+
+	run := New(Tool{Name: "go-serum-analyzer"}, result, testdata).Runs[0]
+	if len(run.Tool.Driver.Rules) != len(analysis.Categories())+1 {
+		t.Errorf("expected a rule per category and one for the unknown category, but found %d rules", len(run.Tool.Driver.Rules))
+	}
+	for _, res := range run.Results {
+		if run.Tool.Driver.Rules[res.RuleIndex].ID != res.RuleID {
+			t.Errorf("result of rule %q refers to rule %q", res.RuleID, run.Tool.Driver.Rules[res.RuleIndex].ID)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package mismatch
+
+type Error struct {
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
+
+// Open opens something.
+//
+// Errors:
+//
+//    - mismatch-not-fuond -- if nothing was found
+func Open(name string) error {
+	if name == "" {
+		return &Error{"mismatch-invalid"}
+	}
+	return &Error{"mismatch-not-found"}
+}
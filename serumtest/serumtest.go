@@ -0,0 +1,34 @@
+// Package serumtest provides test helpers asserting the error codes of returned errors.
+//
+// The serum analyzer recognises calls of ExpectCode: it reports expected codes,
+// that are not declared by the function the error comes from.
+// The go-serum-coverage command with the -tests flag lists the declared codes, that no test expects.
+package serumtest
+
+// TestingT is the subset of testing.TB used by the helpers of this package.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Code returns the code of the given error, or the empty string if the error is nil or has no Code() method.
+//
+// Like the analyzer, only the error itself is looked at, not the errors it wraps.
+func Code(err error) string {
+	coded, ok := err.(interface{ Code() string })
+	if !ok {
+		return ""
+	}
+	return coded.Code()
+}
+
+// ExpectCode reports a test failure, if the given error is nil or does not have the given code.
+func ExpectCode(t TestingT, err error, code string) {
+	t.Helper()
+	switch actual := Code(err); {
+	case err == nil:
+		t.Errorf("expected error with code %q, but got no error", code)
+	case actual != code:
+		t.Errorf("expected error with code %q, but got code %q: %v", code, actual, err)
+	}
+}
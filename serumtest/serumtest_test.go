@@ -0,0 +1,66 @@
+package serumtest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type codedError struct {
+	code string
+}
+
+func (e *codedError) Code() string  { return e.code }
+func (e *codedError) Error() string { return "error " + e.code }
+
+type recorder struct {
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{nil, ""},
+		{errors.New("plain"), ""},
+		{&codedError{"pkg-error"}, "pkg-error"},
+		{fmt.Errorf("wrapped: %w", &codedError{"pkg-error"}), ""},
+	}
+
+	for _, test := range tests {
+		if code := Code(test.err); code != test.expected {
+			t.Errorf("Code(%v) should return %q, but returned %q", test.err, test.expected, code)
+		}
+	}
+}
+
+func TestExpectCode(t *testing.T) {
+	tests := []struct {
+		err     error
+		failure string
+	}{
+		{&codedError{"pkg-error"}, ""},
+		{nil, `expected error with code "pkg-error", but got no error`},
+		{&codedError{"pkg-other"}, `expected error with code "pkg-error", but got code "pkg-other": error pkg-other`},
+		{errors.New("plain"), `expected error with code "pkg-error", but got code "": plain`},
+	}
+
+	for _, test := range tests {
+		r := &recorder{}
+		ExpectCode(r, test.err, "pkg-error")
+
+		switch {
+		case test.failure == "" && len(r.failures) > 0:
+			t.Errorf("ExpectCode(%v) reported unexpected failures: %v", test.err, r.failures)
+		case test.failure != "" && (len(r.failures) != 1 || r.failures[0] != test.failure):
+			t.Errorf("ExpectCode(%v) should report %q, but reported %v", test.err, test.failure, r.failures)
+		}
+	}
+}
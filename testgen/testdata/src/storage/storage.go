@@ -0,0 +1,41 @@
+package storage
+
+// Open opens the storage.
+//
+// Errors:
+//
+//    - storage-not-found -- if the "name" does not exist
+//    - storage-denied -- if access was denied
+func Open(name string) (*Store, error) {
+	if name == "" {
+		return nil, &Error{"storage-not-found"}
+	}
+	return nil, &Error{"storage-denied"}
+}
+
+type Store struct{}
+
+// Get gets a value.
+//
+// Errors:
+//
+//    - storage-missing-key --
+func (s *Store) Get(key string) (string, error) {
+	return "", &Error{"storage-missing-key"}
+}
+
+type Getter interface {
+	// Get gets a value.
+	//
+	// Errors:
+	//
+	//    - storage-missing-key --
+	Get(key string) (string, error)
+}
+
+type Error struct {
+	code string
+}
+
+func (e *Error) Code() string  { return e.code }
+func (e *Error) Error() string { return e.code }
@@ -0,0 +1,299 @@
+// Package testgen generates table-driven test skeletons for the error codes declared by a function.
+//
+// The generated test has one case per declared code, named after the code and containing its description from the docstring.
+// Each case checks the returned error with serumtest.ExpectCode, the call of the function is left to the author of the test.
+// Until the call is filled in, the checked error is nil, so the generated cases fail.
+// The analyzer resolves the codes of the table in the call of serumtest.ExpectCode,
+// so once the error is assigned from the call, the cases count as tests of the codes (see coverage.Untested).
+//
+// Existing tests are never overwritten: if the test function already exists, only the cases of missing codes are appended
+// to its table, and if the file exists, but not the test function, the function is appended to the file.
+package testgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+const serumtestPath = "github.com/serum-errors/go-serum-analyzer/serumtest"
+
+// Function is a function or method declaring error codes, for which a test is generated.
+type Function struct {
+	Package string // name of the package declaring the function
+	Name    string // name as used in the catalog, e.g. "Open" or "(*Store).Get"
+	File    string // path of the file declaring the function
+
+	Codes []*catalog.Code // declared codes with their descriptions, sorted by code
+}
+
+// TestName returns the name of the generated test function, e.g. "TestOpenErrors" or "TestStore_GetErrors".
+func (fn *Function) TestName() string {
+	name := strings.NewReplacer("(", "", ")", "", "*", "", ".", "_").Replace(fn.Name)
+	return "Test" + name + "Errors"
+}
+
+// TestFile returns the default file of the generated test: the test file belonging to the file declaring the function.
+func (fn *Function) TestFile() string {
+	return strings.TrimSuffix(fn.File, ".go") + "_test.go"
+}
+
+// Find finds the function or method with the given name in the packages of the given result.
+// The name is given as in the catalog (see catalog.FunctionName). Interface methods are not supported.
+//
+// Find returns an error, if there is no such function declaring error codes, or if the name is ambiguous.
+func Find(result *driver.Result, name string) (*Function, error) {
+	var found []*Function
+	for _, pkg := range result.Packages {
+		for _, objectFact := range result.ObjectFacts(pkg.Types) {
+			fact, ok := objectFact.Fact.(*analysis.ErrorCodes)
+			fn, isFunc := objectFact.Object.(*types.Func)
+			if !ok || !isFunc {
+				continue
+			}
+			if fnName, _ := catalog.FunctionName(fn); fnName != name {
+				continue
+			}
+
+			funcDecl := findFuncDecl(pkg.Syntax, fn)
+			if funcDecl == nil {
+				return nil, fmt.Errorf("%s.%s is an interface method", pkg.PkgPath, name)
+			}
+			found = append(found, newFunction(pkg, fn, fact, funcDecl))
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("function %q declaring error codes not found", name)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("function %q is declared in %d packages", name, len(found))
+	}
+}
+
+func findFuncDecl(files []*ast.File, fn *types.Func) *ast.FuncDecl {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Name.Pos() == fn.Pos() {
+				return funcDecl
+			}
+		}
+	}
+	return nil
+}
+
+func newFunction(pkg *driver.Package, fn *types.Func, fact *analysis.ErrorCodes, funcDecl *ast.FuncDecl) *Function {
+	name, _ := catalog.FunctionName(fn)
+	function := &Function{
+		Package: pkg.Name,
+		Name:    name,
+		File:    pkg.Fset.File(fn.Pos()).Name(),
+	}
+
+	// The docstring was already validated by the analyzer, so errors are not expected here.
+	errorDocs := &analysis.ErrorDocs{}
+	if funcDecl.Doc != nil {
+		if parsed, err := analysis.ParseErrorDocs(funcDecl.Doc.Text()); err == nil && parsed != nil {
+			errorDocs = parsed
+		}
+	}
+
+	codes := fact.Codes.Slice()
+	sort.Strings(codes)
+	for _, code := range codes {
+		function.Codes = append(function.Codes, &catalog.Code{Code: code, Description: errorDocs.Descriptions[code]})
+	}
+	return function
+}
+
+// Generate generates the test of the given function, and adds it to the given content of an existing test file.
+// If existing is empty, a new test file is generated.
+//
+// The second result contains the codes, for which cases were added. It is empty if the test already contains all codes,
+// in which case the existing content is returned unchanged.
+func Generate(existing []byte, fn *Function) ([]byte, []string, error) {
+	if len(bytes.TrimSpace(existing)) == 0 {
+		// New test files are internal tests, so unexported functions can be called.
+		var buffer bytes.Buffer
+		fmt.Fprintf(&buffer, "package %s\n\n", fn.Package)
+		fmt.Fprintf(&buffer, "import (\n\t\"testing\"\n\n\t%q\n)\n\n", serumtestPath)
+		writeTest(&buffer, fn)
+		return formatSource(buffer.Bytes(), allCodes(fn.Codes))
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", existing, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse existing test file: %v", err)
+	}
+
+	testFunc := findTestFunc(file, fn.TestName())
+	if testFunc == nil {
+		var buffer bytes.Buffer
+		buffer.Write(bytes.TrimRight(existing, "\n"))
+		buffer.WriteString("\n\n")
+		writeTest(&buffer, fn)
+		source, added, err := formatSource(buffer.Bytes(), allCodes(fn.Codes))
+		if err != nil {
+			return nil, nil, err
+		}
+		source, err = addImports(source, "testing", serumtestPath)
+		return source, added, err
+	}
+
+	table := findTestTable(testFunc)
+	if table == nil {
+		return nil, nil, fmt.Errorf("test function %s does not contain a table named \"tests\"", fn.TestName())
+	}
+
+	existingCodes := findTableCodes(table)
+	var missing []*catalog.Code
+	for _, code := range fn.Codes {
+		if _, ok := existingCodes[code.Code]; !ok {
+			missing = append(missing, code)
+		}
+	}
+	if len(missing) == 0 {
+		return existing, nil, nil
+	}
+
+	// Insert the missing cases in front of the closing brace of the table.
+	var cases bytes.Buffer
+	if len(table.Elts) == 0 {
+		cases.WriteString("\n")
+	} else {
+		lastEnd := fset.Position(table.Elts[len(table.Elts)-1].End()).Offset
+		if !bytes.Contains(existing[lastEnd:fset.Position(table.Rbrace).Offset], []byte(",")) {
+			cases.WriteString(",\n")
+		}
+	}
+	writeCases(&cases, missing)
+
+	offset := fset.Position(table.Rbrace).Offset
+	var buffer bytes.Buffer
+	buffer.Write(existing[:offset])
+	buffer.Write(cases.Bytes())
+	buffer.Write(existing[offset:])
+	return formatSource(buffer.Bytes(), allCodes(missing))
+}
+
+func writeTest(buffer *bytes.Buffer, fn *Function) {
+	fmt.Fprintf(buffer, "func %s(t *testing.T) {\n", fn.TestName())
+	buffer.WriteString("\ttests := []struct {\n\t\tcode        string\n\t\tdescription string\n\t}{\n")
+	writeCases(buffer, fn.Codes)
+	buffer.WriteString("\t}\n\n")
+	buffer.WriteString("\tfor _, test := range tests {\n")
+	buffer.WriteString("\t\tt.Run(test.code, func(t *testing.T) {\n")
+	fmt.Fprintf(buffer, "\t\t\t// TODO: Call %s, so it returns an error with the code of the test case.\n", fn.Name)
+	buffer.WriteString("\t\t\t// The test fails until then, because err is nil.\n")
+	buffer.WriteString("\t\t\tvar err error\n")
+	buffer.WriteString("\t\t\tserumtest.ExpectCode(t, err, test.code)\n")
+	buffer.WriteString("\t\t})\n\t}\n}\n")
+}
+
+func writeCases(buffer *bytes.Buffer, codes []*catalog.Code) {
+	for _, code := range codes {
+		fmt.Fprintf(buffer, "\t\t{code: %s, description: %s},\n", strconv.Quote(code.Code), strconv.Quote(code.Description))
+	}
+}
+
+func allCodes(codes []*catalog.Code) []string {
+	result := make([]string, 0, len(codes))
+	for _, code := range codes {
+		result = append(result, code.Code)
+	}
+	return result
+}
+
+func formatSource(source []byte, added []string) ([]byte, []string, error) {
+	formatted, err := format.Source(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not format generated test: %v", err)
+	}
+	return formatted, added, nil
+}
+
+// addImports adds the given imports to the given source, if they are missing.
+func addImports(source []byte, paths ...string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		astutil.AddImport(fset, file, path)
+	}
+
+	var buffer bytes.Buffer
+	if err := format.Node(&buffer, fset, file); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func findTestFunc(file *ast.File, name string) *ast.FuncDecl {
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && funcDecl.Name.Name == name && funcDecl.Body != nil {
+			return funcDecl
+		}
+	}
+	return nil
+}
+
+// findTestTable finds the composite literal assigned to the variable "tests" in the given test function.
+func findTestTable(testFunc *ast.FuncDecl) *ast.CompositeLit {
+	var result *ast.CompositeLit
+	ast.Inspect(testFunc.Body, func(node ast.Node) bool {
+		assignStmt, ok := node.(*ast.AssignStmt)
+		if !ok || result != nil || len(assignStmt.Lhs) != 1 || len(assignStmt.Rhs) != 1 {
+			return result == nil
+		}
+		ident, ok := assignStmt.Lhs[0].(*ast.Ident)
+		compositeLit, isLit := assignStmt.Rhs[0].(*ast.CompositeLit)
+		if ok && isLit && ident.Name == "tests" {
+			result = compositeLit
+		}
+		return result == nil
+	})
+	return result
+}
+
+// findTableCodes finds the string constants of the "code" fields of the cases in the given table.
+func findTableCodes(table *ast.CompositeLit) analysis.CodeSet {
+	codes := analysis.Set()
+	for _, element := range table.Elts {
+		testCase, ok := element.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		for _, field := range testCase.Elts {
+			keyValue, ok := field.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := keyValue.Key.(*ast.Ident)
+			value, isLit := keyValue.Value.(*ast.BasicLit)
+			if !ok || !isLit || key.Name != "code" || value.Kind != token.STRING {
+				continue
+			}
+			if code, err := strconv.Unquote(value.Value); err == nil {
+				codes.Add(code)
+			}
+		}
+	}
+	return codes
+}
@@ -0,0 +1,167 @@
+package testgen
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/serum-errors/go-serum-analyzer/analysis"
+	"github.com/serum-errors/go-serum-analyzer/catalog"
+	"github.com/serum-errors/go-serum-analyzer/driver"
+	"golang.org/x/tools/go/packages"
+)
+
+func loadTestdata(t *testing.T, patterns ...string) *driver.Result {
+	t.Helper()
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &packages.Config{
+		Dir: testdata,
+		Env: append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off", "GOPROXY=off"),
+	}
+	result, err := driver.Run(analysis.Analyzer, config, patterns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+var testFunction = &Function{
+	Package: "storage",
+	Name:    "Open",
+	File:    "storage.go",
+	Codes: []*catalog.Code{
+		{Code: "storage-denied", Description: "if access was denied"},
+		{Code: "storage-not-found", Description: `if the "name" does not exist`},
+	},
+}
+
+func TestFind(t *testing.T) {
+	result := loadTestdata(t, "storage")
+
+	fn, err := Find(result, "Open")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn.File = filepath.Base(fn.File)
+	if !reflect.DeepEqual(fn, testFunction) {
+		t.Errorf("Find returned unexpected function: %+v", fn)
+	}
+
+	fn, err = Find(result, "(*Store).Get")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fn.TestName() != "TestStore_GetErrors" || filepath.Base(fn.TestFile()) != "storage_test.go" {
+		t.Errorf("unexpected test name %q or file %q", fn.TestName(), fn.TestFile())
+	}
+
+	for name, expected := range map[string]string{
+		"Close":      `function "Close" declaring error codes not found`,
+		"Getter.Get": "storage.Getter.Get is an interface method",
+	} {
+		if _, err := Find(result, name); err == nil || err.Error() != expected {
+			t.Errorf("Find(%q) should return error %q but returned: %v", name, expected, err)
+		}
+	}
+}
+
+const generatedTest = `func TestOpenErrors(t *testing.T) {
+	tests := []struct {
+		code        string
+		description string
+	}{
+		{code: "storage-denied", description: "if access was denied"},
+		{code: "storage-not-found", description: "if the \"name\" does not exist"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.code, func(t *testing.T) {
+			// TODO: Call Open, so it returns an error with the code of the test case.
+			// The test fails until then, because err is nil.
+			var err error
+			serumtest.ExpectCode(t, err, test.code)
+		})
+	}
+}
+`
+
+func TestGenerateNewFile(t *testing.T) {
+	source, added, err := Generate(nil, testFunction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "package storage\n\nimport (\n\t\"testing\"\n\n\t\"github.com/serum-errors/go-serum-analyzer/serumtest\"\n)\n\n" + generatedTest
+	if string(source) != expected {
+		t.Errorf("unexpected test file:\n%s", source)
+	}
+	if !reflect.DeepEqual(added, []string{"storage-denied", "storage-not-found"}) {
+		t.Errorf("unexpected added codes: %v", added)
+	}
+}
+
+func TestGenerateAppendFunction(t *testing.T) {
+	existing := "package storage_test\n\nimport \"strings\"\n\nfunc TestOther(t *testing.T) {\n\t_ = strings.ToLower(\"\")\n}\n"
+
+	source, _, err := Generate([]byte(existing), testFunction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "package storage_test\n\nimport (\n\t\"github.com/serum-errors/go-serum-analyzer/serumtest\"\n\t\"strings\"\n\t\"testing\"\n)\n\n" +
+		"func TestOther(t *testing.T) {\n\t_ = strings.ToLower(\"\")\n}\n\n" + generatedTest
+	if string(source) != expected {
+		t.Errorf("unexpected test file:\n%s", source)
+	}
+}
+
+func TestGenerateAppendCases(t *testing.T) {
+	existing := `package storage
+
+func TestOpenErrors(t *testing.T) {
+	tests := []struct {
+		code  string
+		setup func()
+	}{
+		{code: "storage-denied", setup: func() {}}, // keep this
+	}
+	_ = tests
+}
+`
+
+	source, added, err := Generate([]byte(existing), testFunction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(added, []string{"storage-not-found"}) {
+		t.Errorf("unexpected added codes: %v", added)
+	}
+	if !strings.Contains(string(source), `		{code: "storage-denied", setup: func() {}}, // keep this
+		{code: "storage-not-found", description: "if the \"name\" does not exist"},
+	}`) {
+		t.Errorf("missing case was not appended:\n%s", source)
+	}
+
+	// Running the generator again does not change anything.
+	again, added, err := Generate(source, testFunction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 0 || string(again) != string(source) {
+		t.Errorf("existing test should not be changed, but added %v:\n%s", added, again)
+	}
+}
+
+func TestGenerateWithoutTable(t *testing.T) {
+	existing := "package storage\n\nfunc TestOpenErrors(t *testing.T) {}\n"
+	_, _, err := Generate([]byte(existing), testFunction)
+	if err == nil || !strings.Contains(err.Error(), `does not contain a table named "tests"`) {
+		t.Errorf("Generate should refuse to change the test, but returned: %v", err)
+	}
+}